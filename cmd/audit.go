@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Score each component of a Spin application on its attack surface",
+	Long: `The "audit" command reads a spin.toml file and scores every component on its attack surface:
+public HTTP routes, wildcard outbound hosts, plaintext and database outbound hosts,
+secret variables referenced through templates and key-value stores or databases shared with other components.
+Components are ranked from the highest score (largest attack surface) to the lowest.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		tomlData, _, err := loadApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		audits := auditComponents(tomlData)
+
+		switch format {
+		case "table":
+			fmt.Print(showAudit(audits))
+		case "markdown":
+			fmt.Print(auditToMarkdown(audits))
+		case "json":
+			out, err := json.MarshalIndent(audits, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+		default:
+			return fmt.Errorf("unknown format %q, expected \"table\", \"json\" or \"markdown\"", format)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	auditCmd.Flags().String("format", "table", "The output format, either \"table\", \"json\" or \"markdown\"")
+}
+
+// The weight each finding adds to a component's risk score
+const (
+	scorePublicRoute   = 3
+	scoreWildcardHost  = 5
+	scorePlaintextHost = 2
+	scoreDatabaseHost  = 2
+	scoreSecretVar     = 2
+	scoreSharedStore   = 2
+)
+
+// Outbound host schemes that connect directly to a database
+var databaseSchemes = map[string]bool{
+	"postgres":   true,
+	"postgresql": true,
+	"mysql":      true,
+	"redis":      true,
+	"mongodb":    true,
+	"mssql":      true,
+}
+
+// ComponentAudit is the attack surface of a single component
+type ComponentAudit struct {
+	Component      string   `json:"component"`
+	Score          int      `json:"score"`
+	PublicRoutes   []string `json:"public_routes"`
+	PrivateRoutes  int      `json:"private_routes"`
+	WildcardHosts  []string `json:"wildcard_hosts"`
+	PlaintextHosts []string `json:"plaintext_hosts"`
+	DatabaseHosts  []string `json:"database_hosts"`
+	SecretVars     []string `json:"secret_variables"`
+	SharedStores   []string `json:"shared_stores"`
+}
+
+// auditComponents scores every component in the application, ranked from the highest score to the lowest
+func auditComponents(tomlData *SpinTOML) []ComponentAudit {
+	// Counting how many components use each store, so stores used by more than one can be flagged
+	storeUsers := make(map[string]int)
+	for _, componentData := range tomlData.Component {
+		for _, store := range componentStores(componentData) {
+			storeUsers[store]++
+		}
+	}
+
+	var audits []ComponentAudit
+	for name, componentData := range tomlData.Component {
+		audit := ComponentAudit{Component: name}
+
+		for _, httpTrigger := range tomlData.Trigger.HTTP {
+			if httpTrigger.Component != name {
+				continue
+			}
+			if httpTrigger.Route.String != "" {
+				audit.PublicRoutes = append(audit.PublicRoutes, fullRoute(tomlData.Application.Trigger.HTTP.Base, httpTrigger.Route.String))
+			} else {
+				audit.PrivateRoutes++
+			}
+		}
+
+		for _, host := range componentData.AllowedOutboundHosts {
+			if strings.Contains(host, "*") {
+				audit.WildcardHosts = append(audit.WildcardHosts, host)
+			}
+
+			scheme, _, _ := strings.Cut(host, "://")
+			if databaseSchemes[scheme] {
+				audit.DatabaseHosts = append(audit.DatabaseHosts, host)
+			} else if _, isInternal := parseServiceChainingHost(host); scheme == "http" && !isInternal {
+				// Calls to other components never leave the Spin host, so they are not plaintext on the network
+				audit.PlaintextHosts = append(audit.PlaintextHosts, host)
+			}
+		}
+
+		secretVars := make(map[string]bool)
+		for _, varValue := range componentData.Variables {
+			for _, ref := range templateVarRefs(varValue) {
+				if tomlData.Variables[ref].Secret {
+					secretVars[ref] = true
+				}
+			}
+		}
+		for secretVar := range secretVars {
+			audit.SecretVars = append(audit.SecretVars, secretVar)
+		}
+		sort.Strings(audit.SecretVars)
+
+		for _, store := range componentStores(componentData) {
+			if storeUsers[store] > 1 {
+				audit.SharedStores = append(audit.SharedStores, store)
+			}
+		}
+
+		audit.Score = len(audit.PublicRoutes)*scorePublicRoute +
+			len(audit.WildcardHosts)*scoreWildcardHost +
+			len(audit.PlaintextHosts)*scorePlaintextHost +
+			len(audit.DatabaseHosts)*scoreDatabaseHost +
+			len(audit.SecretVars)*scoreSecretVar +
+			len(audit.SharedStores)*scoreSharedStore
+
+		audits = append(audits, audit)
+	}
+
+	sort.Slice(audits, func(i, j int) bool {
+		if audits[i].Score != audits[j].Score {
+			return audits[i].Score > audits[j].Score
+		}
+		return audits[i].Component < audits[j].Component
+	})
+
+	return audits
+}
+
+// componentStores returns the key-value stores and SQLite databases a component can access
+func componentStores(componentData Component) []string {
+	var stores []string
+	for _, kvStore := range componentData.KeyValueStores {
+		stores = append(stores, "kv:"+kvStore)
+	}
+	for _, sqliteDB := range componentData.SQLiteDatabases {
+		stores = append(stores, "sqlite:"+sqliteDB)
+	}
+
+	return stores
+}
+
+// auditTable builds the ranked audit table shared by the terminal and Markdown outputs
+func auditTable(audits []ComponentAudit) table.Writer {
+	auditTable := table.NewWriter()
+	auditTable.AppendHeader(table.Row{"rank", "component", "score", "public_routes", "private_routes", "wildcard_hosts", "plaintext_hosts", "database_hosts", "secret_vars", "shared_stores"})
+
+	for i, audit := range audits {
+		auditTable.AppendRow(table.Row{
+			i + 1,
+			audit.Component,
+			audit.Score,
+			strings.Join(audit.PublicRoutes, "\n"),
+			audit.PrivateRoutes,
+			strings.Join(audit.WildcardHosts, "\n"),
+			strings.Join(audit.PlaintextHosts, "\n"),
+			strings.Join(audit.DatabaseHosts, "\n"),
+			strings.Join(audit.SecretVars, "\n"),
+			strings.Join(audit.SharedStores, "\n"),
+		})
+	}
+
+	return auditTable
+}
+
+// showAudit will display a table with the audit results, ranked by score
+func showAudit(audits []ComponentAudit) string {
+	auditTable := auditTable(audits)
+	auditTable.SetTitle("Security Audit")
+
+	return "\n" + auditTable.Render() + "\n"
+}
+
+// auditToMarkdown renders the audit results as a Markdown report
+func auditToMarkdown(audits []ComponentAudit) string {
+	// Wrapping values in code spans, so routes and hosts are not interpreted as Markdown.
+	// The lists are copied, as the caller may still render the audits in another format.
+	wrapped := make([]ComponentAudit, len(audits))
+	copy(wrapped, audits)
+	for i := range wrapped {
+		for _, list := range []*[]string{&wrapped[i].PublicRoutes, &wrapped[i].WildcardHosts, &wrapped[i].PlaintextHosts, &wrapped[i].DatabaseHosts, &wrapped[i].SecretVars, &wrapped[i].SharedStores} {
+			codeSpans := make([]string, len(*list))
+			for j, value := range *list {
+				codeSpans[j] = "`" + value + "`"
+			}
+			*list = codeSpans
+		}
+	}
+
+	report := "# Security Audit\n\n" + auditTable(wrapped).RenderMarkdown() + "\n\n" +
+		fmt.Sprintf("Scores: public route %d, wildcard host %d, plaintext host %d, database host %d, secret variable %d, shared store %d.\n",
+			scorePublicRoute, scoreWildcardHost, scorePlaintextHost, scoreDatabaseHost, scoreSecretVar, scoreSharedStore)
+
+	return report
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAuditComponents(t *testing.T) {
+	tomlData := &SpinTOML{
+		Variables: map[string]Variable{
+			"api_key": {Secret: true},
+			"region":  {Default: "us-east-1"},
+		},
+		Application: Application{
+			Trigger: ApplicationTrigger{
+				HTTP: ApplicationTriggerHTTP{Base: "/api/"},
+			},
+		},
+		Trigger: Trigger{
+			HTTP: []HTTPTrigger{
+				{Route: Route{String: "/..."}, Component: "gateway"},
+				{Route: Route{Struct: &struct{ Private bool }{Private: true}}, Component: "backend"},
+			},
+		},
+		Component: map[string]Component{
+			"gateway": {
+				AllowedOutboundHosts: []string{"*://*:*", "http://backend.spin.internal"},
+				Variables:            map[string]string{"key": "{{ api_key }}", "region": "{{ region }}"},
+				KeyValueStores:       []string{"default"},
+			},
+			"backend": {
+				AllowedOutboundHosts: []string{"http://example.com", "postgres://db:5432"},
+				KeyValueStores:       []string{"default"},
+				SQLiteDatabases:      []string{"default"},
+			},
+		},
+	}
+
+	want := []ComponentAudit{
+		{
+			Component:     "gateway",
+			Score:         scorePublicRoute + scoreWildcardHost + scoreSecretVar + scoreSharedStore,
+			PublicRoutes:  []string{"/api/..."},
+			WildcardHosts: []string{"*://*:*"},
+			SecretVars:    []string{"api_key"},
+			SharedStores:  []string{"kv:default"},
+		},
+		{
+			Component:      "backend",
+			Score:          scorePlaintextHost + scoreDatabaseHost + scoreSharedStore,
+			PrivateRoutes:  1,
+			PlaintextHosts: []string{"http://example.com"},
+			DatabaseHosts:  []string{"postgres://db:5432"},
+			SharedStores:   []string{"kv:default"},
+		},
+	}
+
+	if diff := cmp.Diff(want, auditComponents(tomlData)); diff != "" {
+		t.Errorf("auditComponents() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestAuditToMarkdownKeepsAudits(t *testing.T) {
+	audits := []ComponentAudit{{Component: "gateway", PublicRoutes: []string{"/api/..."}, SharedStores: []string{"kv:default"}}}
+	want := []ComponentAudit{{Component: "gateway", PublicRoutes: []string{"/api/..."}, SharedStores: []string{"kv:default"}}}
+
+	auditToMarkdown(audits)
+
+	if diff := cmp.Diff(want, audits); diff != "" {
+		t.Errorf("auditToMarkdown() modified the audits (-want +got):\n%s", diff)
+	}
+}
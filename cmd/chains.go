@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var chainsCmd = &cobra.Command{
+	Use:   "chains",
+	Short: "Display the component-to-component calls made through local service chaining",
+	Long: `The "chains" command reads a spin.toml file and finds every component that is allowed to call
+another component through a "http://<component>.spin.internal" outbound host (including wildcards).
+Calls that target a component that does not exist, or a component without an HTTP trigger, are flagged.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		tomlData, _, err := loadApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		edges := buildChainGraph(tomlData)
+
+		switch format {
+		case "table":
+			fmt.Print(showChains(edges))
+		case "dot":
+			fmt.Print(chainsToDot(edges))
+		default:
+			return fmt.Errorf("unknown format %q, expected \"table\" or \"dot\"", format)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	chainsCmd.Flags().String("format", "table", "The output format, either \"table\" or \"dot\" (Graphviz)")
+}
+
+// The suffix Spin uses to route outbound requests to other components in the same application
+const serviceChainingSuffix = ".spin.internal"
+
+// ChainEdge is a single call from one component to another, as allowed by an outbound host
+type ChainEdge struct {
+	Caller string
+	Target string
+	// The allowed outbound host that permits this call
+	Host string
+	// A description of why this call cannot succeed, or blank if the target is valid
+	Problem string
+}
+
+// buildChainGraph finds every "*.spin.internal" outbound host in the application and
+// resolves it to the component(s) it targets
+func buildChainGraph(tomlData *SpinTOML) []ChainEdge {
+	httpComponents := make(map[string]bool)
+	for _, httpTrigger := range tomlData.Trigger.HTTP {
+		httpComponents[httpTrigger.Component] = true
+	}
+
+	var callers []string
+	for name := range tomlData.Component {
+		callers = append(callers, name)
+	}
+	sort.Strings(callers)
+
+	var edges []ChainEdge
+	for _, caller := range callers {
+		for _, host := range tomlData.Component[caller].AllowedOutboundHosts {
+			target, ok := parseServiceChainingHost(host)
+			if !ok {
+				continue
+			}
+
+			// A wildcard allows calls to every component that can receive HTTP requests
+			if target == "*" {
+				var targets []string
+				for name := range httpComponents {
+					if name != caller {
+						targets = append(targets, name)
+					}
+				}
+				sort.Strings(targets)
+
+				if len(targets) == 0 {
+					edges = append(edges, ChainEdge{Caller: caller, Target: target, Host: host, Problem: "no other component has an HTTP trigger"})
+				}
+				for _, name := range targets {
+					edges = append(edges, ChainEdge{Caller: caller, Target: name, Host: host})
+				}
+				continue
+			}
+
+			edge := ChainEdge{Caller: caller, Target: target, Host: host}
+			if _, ok := tomlData.Component[target]; !ok {
+				edge.Problem = "component does not exist"
+			} else if !httpComponents[target] {
+				edge.Problem = "component has no HTTP trigger"
+			}
+			edges = append(edges, edge)
+		}
+	}
+
+	return edges
+}
+
+// parseServiceChainingHost returns the component name targeted by an allowed outbound host
+// such as "http://component.spin.internal" or "*://*.spin.internal:*". The second return
+// value is false if the host is not used for local service chaining.
+func parseServiceChainingHost(host string) (string, bool) {
+	scheme, rest, ok := strings.Cut(host, "://")
+	if !ok {
+		return "", false
+	}
+
+	switch scheme {
+	case "http", "https", "*":
+	default:
+		return "", false
+	}
+
+	// Dropping any path and port, neither of which affect the target component
+	rest, _, _ = strings.Cut(rest, "/")
+	hostname, _, _ := strings.Cut(rest, ":")
+	hostname = strings.ToLower(hostname)
+
+	if !strings.HasSuffix(hostname, serviceChainingSuffix) {
+		return "", false
+	}
+
+	name := strings.TrimSuffix(hostname, serviceChainingSuffix)
+	if name == "" {
+		return "", false
+	}
+
+	return name, true
+}
+
+// showChains will display a table with every service chaining call in the application
+func showChains(edges []ChainEdge) string {
+	chainTable := table.NewWriter()
+	chainTable.SetTitle("Service Chains")
+	chainTable.AppendHeader(table.Row{"caller", "target", "allowed_host", "status"})
+
+	for _, edge := range edges {
+		status := "ok"
+		if edge.Problem != "" {
+			status = "ERR: " + strings.ToUpper(edge.Problem)
+		}
+		chainTable.AppendRow(table.Row{edge.Caller, edge.Target, edge.Host, status})
+	}
+
+	if len(edges) == 0 {
+		return "\nNo components use local service chaining\n"
+	}
+
+	return "\n" + chainTable.Render() + "\n"
+}
+
+// chainsToDot renders the service chaining graph in the Graphviz DOT format.
+// Calls that cannot succeed are drawn as dashed red edges.
+func chainsToDot(edges []ChainEdge) string {
+	var sb strings.Builder
+	sb.WriteString("digraph chains {\n")
+	for _, edge := range edges {
+		if edge.Problem != "" {
+			fmt.Fprintf(&sb, "  %q -> %q [style=dashed, color=red, label=%q];\n", edge.Caller, edge.Target, edge.Problem)
+		} else {
+			fmt.Fprintf(&sb, "  %q -> %q;\n", edge.Caller, edge.Target)
+		}
+	}
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
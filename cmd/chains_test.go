@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseServiceChainingHost(t *testing.T) {
+	tests := []struct {
+		name   string
+		host   string
+		want   string
+		wantOK bool
+	}{
+		{name: "plain_http", host: "http://inventory.spin.internal", want: "inventory", wantOK: true},
+		{name: "with_port_and_path", host: "https://inventory.spin.internal:80/api", want: "inventory", wantOK: true},
+		{name: "wildcard_scheme", host: "*://Inventory.spin.internal:*", want: "inventory", wantOK: true},
+		{name: "wildcard_component", host: "http://*.spin.internal", want: "*", wantOK: true},
+		{name: "external_host", host: "https://example.com", wantOK: false},
+		{name: "database_scheme", host: "redis://cache.spin.internal", wantOK: false},
+		{name: "no_scheme", host: "inventory.spin.internal", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseServiceChainingHost(tt.host)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("parseServiceChainingHost(%q) = %q, %v; want %q, %v", tt.host, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBuildChainGraph(t *testing.T) {
+	tomlData := &SpinTOML{
+		Trigger: Trigger{
+			HTTP: []HTTPTrigger{
+				{Component: "frontend"},
+				{Component: "inventory"},
+				{Component: "orders"},
+			},
+			Redis: []RedisTrigger{
+				{Component: "worker"},
+			},
+		},
+		Component: map[string]Component{
+			"frontend": {AllowedOutboundHosts: []string{
+				"http://inventory.spin.internal",
+				"http://worker.spin.internal",
+				"http://missing.spin.internal",
+				"https://example.com",
+			}},
+			"inventory": {},
+			"orders":    {AllowedOutboundHosts: []string{"http://*.spin.internal"}},
+			"worker":    {},
+		},
+	}
+
+	want := []ChainEdge{
+		{Caller: "frontend", Target: "inventory", Host: "http://inventory.spin.internal"},
+		{Caller: "frontend", Target: "worker", Host: "http://worker.spin.internal", Problem: "component has no HTTP trigger"},
+		{Caller: "frontend", Target: "missing", Host: "http://missing.spin.internal", Problem: "component does not exist"},
+		{Caller: "orders", Target: "frontend", Host: "http://*.spin.internal"},
+		{Caller: "orders", Target: "inventory", Host: "http://*.spin.internal"},
+	}
+
+	if diff := cmp.Diff(want, buildChainGraph(tomlData)); diff != "" {
+		t.Errorf("buildChainGraph() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var channelsCmd = &cobra.Command{
+	Use:   "channels",
+	Short: "Display the Redis channels of a Spin application, with their subscribers and publishers",
+	Long: `The "channels" command groups every Redis trigger by its resolved address and channel.
+For each channel, it shows the components subscribed to it and the components whose allowed outbound hosts
+let them connect to that Redis host, and so could publish to it.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		tomlData, envVars, err := loadApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		values := maskSecrets(tomlData, resolveVariables(tomlData, envVars))
+		channels := buildRedisChannels(tomlData, values)
+
+		switch format {
+		case "table":
+			fmt.Print(showRedisChannels(channels))
+		case "dot":
+			fmt.Print(redisChannelsToDot(channels))
+		default:
+			return fmt.Errorf("unknown format %q, expected \"table\" or \"dot\"", format)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	channelsCmd.Flags().String("format", "table", "The output format, either \"table\" or \"dot\" (Graphviz)")
+}
+
+// The port Redis listens on when the address doesn't have one
+const defaultRedisPort = "6379"
+
+// RedisChannel is a channel on a Redis server, with the components on either side of it
+type RedisChannel struct {
+	// The resolved address of the Redis server
+	Address     string
+	Channel     string
+	Subscribers []string
+	// The components allowed to connect to the Redis server, which could publish to the channel
+	Publishers []string
+	// A description of why the address can't be used, or blank if it is valid
+	Problem string
+}
+
+// resolveRedisAddress returns the address a Redis trigger subscribes to, falling back to the application Redis address
+func resolveRedisAddress(tomlData *SpinTOML, redisTrigger RedisTrigger) string {
+	if redisTrigger.Address != "" {
+		return redisTrigger.Address
+	}
+
+	return tomlData.Application.Trigger.Redis.Address
+}
+
+// buildRedisChannels groups the Redis triggers by resolved address and channel, sorted by address then channel
+func buildRedisChannels(tomlData *SpinTOML, vars map[string]VariableValue) []RedisChannel {
+	type channelKey struct{ address, channel string }
+	channels := make(map[channelKey]*RedisChannel)
+
+	for _, redisTrigger := range tomlData.Trigger.Redis {
+		var problem string
+		address, err := resolveTemplate(resolveRedisAddress(tomlData, redisTrigger), vars)
+		if err != nil {
+			address = resolveRedisAddress(tomlData, redisTrigger)
+			problem = err.Error()
+		} else if address == "" {
+			problem = "no Redis address"
+		}
+
+		channel := resolveField(redisTrigger.Channel, vars)
+
+		key := channelKey{address, channel}
+		if channels[key] == nil {
+			channels[key] = &RedisChannel{Address: address, Channel: channel, Problem: problem}
+		}
+		if !slices.Contains(channels[key].Subscribers, redisTrigger.Component) {
+			channels[key].Subscribers = append(channels[key].Subscribers, redisTrigger.Component)
+		}
+	}
+
+	var sorted []RedisChannel
+	for _, channel := range channels {
+		sort.Strings(channel.Subscribers)
+		if channel.Problem == "" {
+			channel.Publishers = redisPublishers(tomlData, channel.Address, vars)
+		}
+		sorted = append(sorted, *channel)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Address != sorted[j].Address {
+			return sorted[i].Address < sorted[j].Address
+		}
+		return sorted[i].Channel < sorted[j].Channel
+	})
+
+	return sorted
+}
+
+// redisPublishers returns the components with an allowed outbound host that permits connecting to the Redis address
+func redisPublishers(tomlData *SpinTOML, address string, vars map[string]VariableValue) []string {
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return nil
+	}
+
+	port := parsed.Port()
+	if port == "" {
+		port = defaultRedisPort
+	}
+
+	var publishers []string
+	for name, componentData := range tomlData.Component {
+		for _, host := range componentData.AllowedOutboundHosts {
+			resolved, err := resolveTemplate(host, vars)
+			if err != nil {
+				continue
+			}
+
+			if outboundHostAllows(resolved, parsed.Scheme, parsed.Hostname(), port) {
+				publishers = append(publishers, name)
+				break
+			}
+		}
+	}
+	sort.Strings(publishers)
+
+	return publishers
+}
+
+// outboundHostAllows reports whether an allowed outbound host such as "redis://*.example.com:6379"
+// permits connecting to the host and port with the scheme. Like in Spin, the scheme, host and port can
+// be "*", the host can be a "*.<domain>" subdomain wildcard and the port can be a range.
+// Without a port, only the scheme's default port is allowed.
+func outboundHostAllows(allowedHost, scheme, hostname, port string) bool {
+	allowedScheme, rest, ok := strings.Cut(allowedHost, "://")
+	if !ok {
+		return false
+	}
+	if allowedScheme != "*" && !strings.EqualFold(allowedScheme, scheme) {
+		return false
+	}
+
+	rest, _, _ = strings.Cut(rest, "/")
+	allowedHostname, allowedPort, hasPort := strings.Cut(rest, ":")
+
+	switch {
+	case allowedHostname == "*":
+	case strings.HasPrefix(allowedHostname, "*."):
+		if !strings.HasSuffix(strings.ToLower(hostname), strings.ToLower(allowedHostname[1:])) {
+			return false
+		}
+	default:
+		if !strings.EqualFold(allowedHostname, hostname) {
+			return false
+		}
+	}
+
+	if !hasPort {
+		// Only Redis addresses are matched here, so the default port is Redis'
+		return port == defaultRedisPort
+	}
+
+	if allowedPort == "*" {
+		return true
+	}
+
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		return false
+	}
+
+	if start, end, isRange := strings.Cut(allowedPort, "-"); isRange {
+		startNumber, startErr := strconv.Atoi(start)
+		endNumber, endErr := strconv.Atoi(end)
+		return startErr == nil && endErr == nil && portNumber >= startNumber && portNumber <= endNumber
+	}
+
+	return allowedPort == port
+}
+
+// showRedisChannels will display a table with the subscribers and publishers of every Redis channel
+func showRedisChannels(channels []RedisChannel) string {
+	if len(channels) == 0 {
+		return "\nNo components have a Redis trigger\n"
+	}
+
+	channelTable := table.NewWriter()
+	channelTable.SetTitle("Redis Channels")
+	channelTable.AppendHeader(table.Row{"address", "channel", "subscribers", "publishers"})
+	for _, channel := range channels {
+		publishers := strings.Join(channel.Publishers, "\n")
+		switch {
+		case channel.Problem != "":
+			publishers = "ERR: " + strings.ToUpper(channel.Problem)
+		case publishers == "":
+			publishers = "none"
+		}
+		channelTable.AppendRow(table.Row{channel.Address, channel.Channel, strings.Join(channel.Subscribers, "\n"), publishers})
+	}
+
+	return "\n" + channelTable.Render() + "\n"
+}
+
+// redisChannelsToDot renders the channels in the Graphviz DOT format, with an edge from every publisher
+// to the channel and from the channel to every subscriber. Publishers are drawn with dashed edges, as they
+// are only allowed to publish, which doesn't mean they do.
+func redisChannelsToDot(channels []RedisChannel) string {
+	var sb strings.Builder
+	sb.WriteString("digraph channels {\n")
+	for _, channel := range channels {
+		node := channel.Address + " " + channel.Channel
+		label := channel.Channel + "\\n" + channel.Address
+		if channel.Problem != "" {
+			fmt.Fprintf(&sb, "  %q [shape=box, color=red, label=\"%s\\n%s\"];\n", node, strings.ReplaceAll(label, `"`, `\"`), strings.ReplaceAll(channel.Problem, `"`, `\"`))
+		} else {
+			fmt.Fprintf(&sb, "  %q [shape=box, label=\"%s\"];\n", node, strings.ReplaceAll(label, `"`, `\"`))
+		}
+
+		for _, publisher := range channel.Publishers {
+			fmt.Fprintf(&sb, "  %q -> %q [style=dashed];\n", publisher, node)
+		}
+		for _, subscriber := range channel.Subscribers {
+			fmt.Fprintf(&sb, "  %q -> %q;\n", node, subscriber)
+		}
+	}
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
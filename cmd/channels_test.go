@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOutboundHostAllows(t *testing.T) {
+	tests := []struct {
+		name        string
+		allowedHost string
+		hostname    string
+		port        string
+		want        bool
+	}{
+		{name: "exact", allowedHost: "redis://cache.example.com:6379", hostname: "cache.example.com", port: "6379", want: true},
+		{name: "default_port", allowedHost: "redis://cache.example.com", hostname: "cache.example.com", port: "6379", want: true},
+		{name: "default_port_mismatch", allowedHost: "redis://cache.example.com", hostname: "cache.example.com", port: "6380", want: false},
+		{name: "wildcard_scheme", allowedHost: "*://cache.example.com:6379", hostname: "cache.example.com", port: "6379", want: true},
+		{name: "other_scheme", allowedHost: "https://cache.example.com:6379", hostname: "cache.example.com", port: "6379", want: false},
+		{name: "wildcard_host", allowedHost: "redis://*:6379", hostname: "anything.io", port: "6379", want: true},
+		{name: "subdomain_wildcard", allowedHost: "redis://*.example.com", hostname: "Cache.Example.com", port: "6379", want: true},
+		{name: "subdomain_wildcard_other_domain", allowedHost: "redis://*.example.com", hostname: "example.org", port: "6379", want: false},
+		{name: "wildcard_port", allowedHost: "redis://cache.example.com:*", hostname: "cache.example.com", port: "7000", want: true},
+		{name: "port_range", allowedHost: "redis://cache.example.com:6000-7000", hostname: "cache.example.com", port: "6379", want: true},
+		{name: "port_outside_range", allowedHost: "redis://cache.example.com:6000-6100", hostname: "cache.example.com", port: "6379", want: false},
+		{name: "other_host", allowedHost: "redis://localhost:6379", hostname: "cache.example.com", port: "6379", want: false},
+		{name: "no_scheme", allowedHost: "cache.example.com:6379", hostname: "cache.example.com", port: "6379", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outboundHostAllows(tt.allowedHost, "redis", tt.hostname, tt.port); got != tt.want {
+				t.Errorf("outboundHostAllows(%q, %q, %q) = %v; want %v", tt.allowedHost, tt.hostname, tt.port, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildRedisChannels(t *testing.T) {
+	tomlData := &SpinTOML{
+		Application: Application{Trigger: ApplicationTrigger{Redis: ApplicationTriggerRedis{Address: "redis://{{ redis_host }}"}}},
+		Trigger: Trigger{
+			Redis: []RedisTrigger{
+				{Channel: "orders", Component: "fulfilment"},
+				{Channel: "orders", Component: "analytics"},
+				{Address: "redis://events.example.com:6380", Channel: "{{ audit_channel }}", Component: "auditor"},
+				{Address: "redis://{{ missing }}", Channel: "lost", Component: "orphan"},
+			},
+		},
+		Component: map[string]Component{
+			"checkout":   {AllowedOutboundHosts: []string{"redis://{{ redis_host }}"}},
+			"fulfilment": {AllowedOutboundHosts: []string{"redis://*.internal:*"}},
+			"analytics":  {AllowedOutboundHosts: []string{"https://cache.internal"}},
+			"emitter":    {AllowedOutboundHosts: []string{"*://*:6380"}},
+			"auditor":    {},
+			"orphan":     {},
+		},
+	}
+	vars := map[string]VariableValue{"redis_host": {Value: "cache.internal"}, "audit_channel": {Value: "audit"}}
+
+	got := buildRedisChannels(tomlData, vars)
+	want := []RedisChannel{
+		{Address: "redis://cache.internal", Channel: "orders", Subscribers: []string{"analytics", "fulfilment"}, Publishers: []string{"checkout", "fulfilment"}},
+		{Address: "redis://events.example.com:6380", Channel: "audit", Subscribers: []string{"auditor"}, Publishers: []string{"emitter"}},
+		{Address: "redis://{{ missing }}", Channel: "lost", Subscribers: []string{"orphan"}, Problem: `offset 8: variable "missing" is not declared`},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("buildRedisChannels() mismatch (-want +got):\n%s", diff)
+	}
+
+	dot := redisChannelsToDot(got)
+	for _, edge := range []string{
+		`"checkout" -> "redis://cache.internal orders" [style=dashed];`,
+		`"redis://cache.internal orders" -> "fulfilment";`,
+		`"redis://events.example.com:6380 audit" -> "auditor";`,
+	} {
+		if !strings.Contains(dot, edge) {
+			t.Errorf("redisChannelsToDot() is missing %s:\n%s", edge, dot)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// ConstraintViolation is a variable whose resolved value doesn't satisfy its "[tool.blueprint.variables.<name>]" constraints
+type ConstraintViolation struct {
+	Variable string
+	Message  string
+}
+
+// validateVariableConstraints checks the resolved value of every constrained variable.
+// Unresolved variables are skipped, as they are already reported by the Variables tables.
+func validateVariableConstraints(tomlData *SpinTOML, resolvedVars map[string]VariableValue) []ConstraintViolation {
+	var varKeys []string
+	for varKey := range tomlData.Tool.Blueprint.Variables {
+		varKeys = append(varKeys, varKey)
+	}
+	sort.Strings(varKeys)
+
+	var violations []ConstraintViolation
+	for _, varKey := range varKeys {
+		constraint := tomlData.Tool.Blueprint.Variables[varKey]
+		varData, declared := tomlData.Variables[varKey]
+		if !declared {
+			violations = append(violations, ConstraintViolation{Variable: varKey, Message: "constraints are defined for a variable that is not declared"})
+			continue
+		}
+
+		resolved := resolvedVars[varKey]
+		if resolved.Source.Kind == SourceUnresolved {
+			continue
+		}
+
+		// The value is only shown in the messages if it isn't secret
+		for _, message := range constraint.check(resolved.Value, maskSecret(resolved.Value, varData.Secret)) {
+			violations = append(violations, ConstraintViolation{Variable: varKey, Message: message + " (from " + resolved.Source.String() + ")"})
+		}
+	}
+
+	return violations
+}
+
+// check returns a message for every constraint the value doesn't satisfy, using shown in place of the value
+func (c VariableConstraint) check(value, shown string) []string {
+	var messages []string
+
+	if c.Pattern != "" {
+		// The pattern must match the whole value, not just a part of it
+		re, err := regexp.Compile("^(?:" + c.Pattern + ")$")
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("invalid pattern %q: %v", c.Pattern, err))
+		} else if !re.MatchString(value) {
+			messages = append(messages, fmt.Sprintf("value %q does not match the pattern %q", shown, c.Pattern))
+		}
+	}
+
+	if len(c.Enum) > 0 && !slices.Contains(c.Enum, value) {
+		messages = append(messages, fmt.Sprintf("value %q is not one of %q", shown, c.Enum))
+	}
+
+	switch c.Format {
+	case "":
+	case "url":
+		if parsed, err := url.Parse(value); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			messages = append(messages, fmt.Sprintf("value %q is not a valid URL", shown))
+		}
+	default:
+		messages = append(messages, fmt.Sprintf("unknown format %q", c.Format))
+	}
+
+	return messages
+}
+
+// showConstraintViolations will display a table with every variable constraint violation
+func showConstraintViolations(violations []ConstraintViolation) string {
+	violationTable := table.NewWriter()
+	violationTable.SetTitle("Variable Constraint Violations")
+	violationTable.AppendHeader(table.Row{"var_key", "violation"})
+	for _, violation := range violations {
+		violationTable.AppendRow(table.Row{violation.Variable, violation.Message})
+	}
+
+	return violationTable.Render()
+}
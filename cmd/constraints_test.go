@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidateVariableConstraints(t *testing.T) {
+	tomlData := &SpinTOML{
+		Variables: map[string]Variable{
+			"api_url":   {},
+			"log_level": {Default: "verbose"},
+			"region":    {},
+			"token":     {Secret: true},
+			"unset":     {},
+		},
+		Tool: Tool{
+			Blueprint: BlueprintTool{
+				Variables: map[string]VariableConstraint{
+					"api_url":    {Format: "url"},
+					"log_level":  {Enum: []string{"debug", "info", "warn", "error"}},
+					"region":     {Pattern: "[a-z]+-[a-z]+-[0-9]"},
+					"token":      {Pattern: "tok_[a-z0-9]+"},
+					"unset":      {Format: "url"},
+					"undeclared": {Format: "url"},
+				},
+			},
+		},
+	}
+
+	envVars := map[string]VariableValue{
+		"api_url": {Value: "localhost:3000", Source: VariableSource{Kind: SourceProcessEnv}},
+		"region":  {Value: "us-east-1a", Source: VariableSource{Kind: SourceDotenv, File: ".env", Line: 2}},
+		"token":   {Value: "not-a-token", Source: VariableSource{Kind: SourceProcessEnv}},
+	}
+
+	want := []ConstraintViolation{
+		{Variable: "api_url", Message: `value "localhost:3000" is not a valid URL (from process env)`},
+		{Variable: "log_level", Message: `value "verbose" is not one of ["debug" "info" "warn" "error"] (from manifest default)`},
+		{Variable: "region", Message: `value "us-east-1a" does not match the pattern "[a-z]+-[a-z]+-[0-9]" (from .env:2)`},
+		{Variable: "token", Message: `value "` + secretMask + `" does not match the pattern "tok_[a-z0-9]+" (from process env)`},
+		{Variable: "undeclared", Message: "constraints are defined for a variable that is not declared"},
+	}
+
+	got := validateVariableConstraints(tomlData, resolveVariables(tomlData, envVars))
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("validateVariableConstraints() mismatch (-want +got):\n%s", diff)
+	}
+}
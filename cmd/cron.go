@@ -0,0 +1,592 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var cronCmd = &cobra.Command{
+	Use:   "cron",
+	Short: "Preview when the cron triggers of a Spin application will fire",
+	Long: `The "cron" command reads the "[[trigger.cron]]" entries in a spin.toml file and shows, for each component,
+a plain-language description of its schedule and its next fire times in the chosen timezone.
+Instants where several components fire at the same time are listed as overlaps.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		count, err := cmd.Flags().GetInt("count")
+		if err != nil {
+			return err
+		}
+		if count < 1 {
+			return fmt.Errorf("the count must be at least 1, got %d", count)
+		}
+
+		tz, err := cmd.Flags().GetString("tz")
+		if err != nil {
+			return err
+		}
+
+		location, err := time.LoadLocation(tz)
+		if err != nil {
+			return fmt.Errorf("unknown timezone %q: %w", tz, err)
+		}
+
+		tomlData, envVars, err := loadApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		values := resolveVariables(tomlData, envVars)
+		previews := previewCronTriggers(tomlData, values, time.Now().In(location), count)
+		fmt.Print(showCronPreviews(previews, findCronOverlaps(previews)))
+		return nil
+	},
+}
+
+func init() {
+	cronCmd.Flags().IntP("count", "n", 5, "The number of upcoming fire times to show for each trigger")
+	cronCmd.Flags().String("tz", "Local", "The IANA timezone to show the fire times in, e.g. \"Europe/Berlin\" or \"UTC\"")
+}
+
+// The fields of a cron expression, in the order of a 7-field expression
+const (
+	cronSecond = iota
+	cronMinute
+	cronHour
+	cronDayOfMonth
+	cronMonth
+	cronDayOfWeek
+	cronYear
+)
+
+var cronFieldNames = []string{"second", "minute", "hour", "day of month", "month", "day of week", "year"}
+
+var cronMonthNames = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+
+var cronDayNames = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// CronSchedule is a parsed cron expression
+type CronSchedule struct {
+	// The fields of the expression, always in 7-field form
+	fields []string
+	// The allowed values of each field, indexed by the value. Days of the week are numbered from 0 (Sunday, i.e. 1 in the expression).
+	allowed [7][]bool
+	// The years the schedule is limited to, or nil for any year
+	years map[int]bool
+}
+
+// parseCron parses a cron expression with 6 (from seconds) or 7 (with years) fields, like Spin's cron trigger.
+// Fields can be "*", "?" (for days), values, names (e.g. "MON" or "JAN"), ranges, lists and "/" steps.
+// Days of the week are numbered 1-7 with Sunday as 1.
+// A day must match both the day of the month and the day of the week, like in Spin's cron trigger.
+func parseCron(expression string) (*CronSchedule, error) {
+	fields := strings.Fields(expression)
+	switch len(fields) {
+	case 5:
+		// Spin rejects crontab expressions, which are easy to copy from elsewhere
+		return nil, fmt.Errorf("expected 6 or 7 fields starting with the seconds, got a 5-field crontab expression (prefix it with \"0 \" to fire at second 0)")
+	case 6:
+		fields = append(fields, "*")
+	case 7:
+	default:
+		return nil, fmt.Errorf("expected 6 or 7 fields, got %d", len(fields))
+	}
+
+	limits := [7][2]int{{0, 59}, {0, 59}, {0, 23}, {1, 31}, {1, 12}, {1, 7}, {1970, 2099}}
+	schedule := &CronSchedule{fields: fields}
+	for i, field := range fields {
+		var names []string
+		switch i {
+		case cronMonth:
+			names = cronMonthNames
+		case cronDayOfWeek:
+			names = cronDayNames
+		}
+
+		values, err := parseCronField(field, limits[i][0], limits[i][1], names, i == cronDayOfMonth || i == cronDayOfWeek)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s field %q: %w", cronFieldNames[i], field, err)
+		}
+
+		switch i {
+		case cronDayOfWeek:
+			// Indexing the days of the week from 0 (Sunday), like time.Weekday
+			schedule.allowed[i] = make([]bool, 7)
+			for _, value := range values {
+				schedule.allowed[i][value-1] = true
+			}
+		case cronYear:
+			if field != "*" {
+				schedule.years = make(map[int]bool)
+				for _, value := range values {
+					schedule.years[value] = true
+				}
+			}
+		default:
+			schedule.allowed[i] = make([]bool, limits[i][1]+1)
+			for _, value := range values {
+				schedule.allowed[i][value] = true
+			}
+		}
+	}
+
+	return schedule, nil
+}
+
+// parseCronField returns every value matched by a field, between min and max (inclusive).
+// Names are matched case-insensitively, the first name having the value 1 (or min for days of the week).
+func parseCronField(field string, min, max int, names []string, allowQuestionMark bool) ([]int, error) {
+	parseValue := func(s string) (int, error) {
+		for i, name := range names {
+			if strings.EqualFold(s, name) {
+				return i + 1, nil
+			}
+		}
+
+		value, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", s)
+		}
+		if value < min || value > max {
+			return 0, fmt.Errorf("%d is out of range %d-%d", value, min, max)
+		}
+
+		return value, nil
+	}
+
+	var values []int
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+
+		var start, end int
+		switch {
+		case rangePart == "*" || (rangePart == "?" && allowQuestionMark):
+			start, end = min, max
+		case strings.Contains(rangePart, "-"):
+			startPart, endPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = parseValue(startPart); err != nil {
+				return nil, err
+			}
+			if end, err = parseValue(endPart); err != nil {
+				return nil, err
+			}
+			if start > end {
+				return nil, fmt.Errorf("the range %q is reversed", rangePart)
+			}
+		default:
+			value, err := parseValue(rangePart)
+			if err != nil {
+				return nil, err
+			}
+			start, end = value, value
+			if hasStep {
+				// "5/15" is shorthand for "5-<max>/15"
+				end = max
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			values = append(values, value)
+		}
+	}
+
+	return values, nil
+}
+
+// The furthest a schedule is searched for its next fire time. Schedules such as
+// "on February 29 when it is a Monday" can take 28 years to fire.
+const cronSearchYears = 30
+
+// Next returns the first fire time strictly after the given time, in the time's location.
+// It returns false if the schedule never fires again.
+func (s *CronSchedule) Next(after time.Time) (time.Time, bool) {
+	location := after.Location()
+	t := after.Truncate(time.Second).Add(time.Second)
+	limit := t.AddDate(cronSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		year, month, day := t.Date()
+		switch {
+		case s.years != nil && !s.years[year]:
+			t = time.Date(year+1, time.January, 1, 0, 0, 0, 0, location)
+		case !s.allowed[cronMonth][month]:
+			t = time.Date(year, month+1, 1, 0, 0, 0, 0, location)
+		case !s.allowed[cronDayOfMonth][day] || !s.allowed[cronDayOfWeek][t.Weekday()]:
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, location)
+		// The time of day is advanced by durations rather than with time.Date, so it never goes back on a DST change
+		case !s.allowed[cronHour][t.Hour()]:
+			t = t.Add(time.Duration(60-t.Minute())*time.Minute - time.Duration(t.Second())*time.Second)
+		case !s.allowed[cronMinute][t.Minute()]:
+			t = t.Add(time.Duration(60-t.Second()) * time.Second)
+		case !s.allowed[cronSecond][t.Second()]:
+			t = t.Add(time.Second)
+		default:
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// NextN returns up to n fire times after the given time
+func (s *CronSchedule) NextN(after time.Time, n int) []time.Time {
+	var times []time.Time
+	for len(times) < n {
+		next, ok := s.Next(after)
+		if !ok {
+			break
+		}
+		times = append(times, next)
+		after = next
+	}
+
+	return times
+}
+
+// Describe returns the schedule in plain language, e.g. "every 5 minutes" or "at 09:00 on Monday through Friday"
+func (s *CronSchedule) Describe() string {
+	second, minute, hour := s.fields[cronSecond], s.fields[cronMinute], s.fields[cronHour]
+
+	var description string
+	secondValue, secondIsValue := cronSingleValue(second)
+	minuteStep, minuteIsStep := cronEveryStep(minute)
+	hourStep, hourIsStep := cronEveryStep(hour)
+	minuteValue, minuteIsValue := cronSingleValue(minute)
+	atSecond := ""
+	if secondIsValue && secondValue != 0 {
+		atSecond = fmt.Sprintf(" at second %d", secondValue)
+	}
+
+	switch {
+	case second == "*" && minute == "*" && hour == "*":
+		description = "every second"
+	case cronIsEvery(second) && minute == "*" && hour == "*":
+		step, _ := cronEveryStep(second)
+		description = fmt.Sprintf("every %d seconds", step)
+	case secondIsValue && minute == "*" && hour == "*":
+		description = "every minute" + atSecond
+	case secondIsValue && minuteIsStep && hour == "*":
+		description = fmt.Sprintf("every %d minutes%s", minuteStep, atSecond)
+	case secondIsValue && minuteIsValue && hour == "*":
+		description = "every hour"
+		if minuteValue != 0 || atSecond != "" {
+			description += fmt.Sprintf(" at minute %d", minuteValue)
+		}
+		description += atSecond
+	case secondIsValue && cronIsList(minute) && hour == "*":
+		description = "every hour at " + describeCronField(minute, "minute", nil) + atSecond
+	case secondIsValue && minuteIsValue && hourIsStep:
+		description = fmt.Sprintf("every %d hours", hourStep)
+		if minuteValue != 0 || atSecond != "" {
+			description += fmt.Sprintf(" at minute %d", minuteValue)
+		}
+		description += atSecond
+	case secondIsValue && cronIsList(minute) && cronIsList(hour) && len(s.valuesOf(cronMinute))*len(s.valuesOf(cronHour)) <= 6:
+		var times []string
+		for _, h := range s.valuesOf(cronHour) {
+			for _, m := range s.valuesOf(cronMinute) {
+				clock := fmt.Sprintf("%02d:%02d", h, m)
+				if secondValue != 0 {
+					clock += fmt.Sprintf(":%02d", secondValue)
+				}
+				times = append(times, clock)
+			}
+		}
+		description = "at " + joinWithAnd(times)
+	default:
+		var parts []string
+		for _, i := range []int{cronSecond, cronMinute, cronHour} {
+			if s.fields[i] != "*" && !(i == cronSecond && s.fields[i] == "0") {
+				parts = append(parts, describeCronField(s.fields[i], cronFieldNames[i], nil))
+			}
+		}
+		description = strings.Join(parts, ", ")
+	}
+
+	if field := s.fields[cronDayOfWeek]; field != "*" && field != "?" {
+		description += " on " + describeCronField(field, "day of the week", s.dayNames())
+	}
+	if field := s.fields[cronDayOfMonth]; field != "*" && field != "?" {
+		description += " on " + describeCronField(field, "day", nil) + " of the month"
+	}
+	if field := s.fields[cronMonth]; field != "*" {
+		description += " in " + describeCronField(field, "month", monthNames())
+	}
+	if field := s.fields[cronYear]; field != "*" {
+		description += " in " + describeCronField(field, "year", nil)
+	}
+
+	return description
+}
+
+// valuesOf returns the allowed values of a field, in order
+func (s *CronSchedule) valuesOf(field int) []int {
+	var values []int
+	for value, allowed := range s.allowed[field] {
+		if allowed {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// dayNames maps the numbers and names used in the day of the week field to the full names of the days
+func (s *CronSchedule) dayNames() map[string]string {
+	names := make(map[string]string)
+	for i := range 7 {
+		day := time.Weekday(i).String()
+		names[strconv.Itoa(i+1)] = day
+		names[cronDayNames[i]] = day
+		names[strings.ToLower(cronDayNames[i])] = day
+	}
+
+	return names
+}
+
+// monthNames maps the numbers and names used in the month field to the full names of the months
+func monthNames() map[string]string {
+	names := make(map[string]string)
+	for i := range 12 {
+		month := time.Month(i + 1).String()
+		names[strconv.Itoa(i+1)] = month
+		names[cronMonthNames[i]] = month
+		names[strings.ToLower(cronMonthNames[i])] = month
+	}
+
+	return names
+}
+
+// cronSingleValue returns the value of a field that is a single number
+func cronSingleValue(field string) (int, bool) {
+	value, err := strconv.Atoi(field)
+	return value, err == nil
+}
+
+// cronEveryStep returns n for a "*/n" field
+func cronEveryStep(field string) (int, bool) {
+	stepPart, ok := strings.CutPrefix(field, "*/")
+	if !ok {
+		return 0, false
+	}
+
+	step, err := strconv.Atoi(stepPart)
+	return step, err == nil
+}
+
+func cronIsEvery(field string) bool {
+	_, ok := cronEveryStep(field)
+	return ok
+}
+
+// cronIsList reports whether a field is a single number or a list of numbers
+func cronIsList(field string) bool {
+	for _, part := range strings.Split(field, ",") {
+		if _, ok := cronSingleValue(part); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// describeCronField describes a field on its own, e.g. "every 5 minutes", "hours 9 through 17" or "Monday through Friday".
+// The names map values to how they are shown, e.g. "1" to "January". Without names, the values are prefixed with the unit.
+func describeCronField(field, unit string, names map[string]string) string {
+	name := func(value string) string {
+		if named, ok := names[value]; ok {
+			return named
+		}
+		return value
+	}
+
+	var parts []string
+	// Whether every part lists values, rather than describing a step
+	valuesOnly := true
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step, hasStep := strings.Cut(part, "/")
+		start, end, isRange := strings.Cut(rangePart, "-")
+		switch {
+		case (rangePart == "*" || rangePart == "?") && hasStep:
+			parts = append(parts, fmt.Sprintf("every %s %ss", step, unit))
+			valuesOnly = false
+		case rangePart == "*" || rangePart == "?":
+			parts = append(parts, "every "+unit)
+			valuesOnly = false
+		case isRange && hasStep:
+			parts = append(parts, fmt.Sprintf("every %s %ss from %s through %s", step, unit, name(start), name(end)))
+			valuesOnly = false
+		case hasStep:
+			parts = append(parts, fmt.Sprintf("every %s %ss from %s", step, unit, name(rangePart)))
+			valuesOnly = false
+		case isRange:
+			parts = append(parts, name(start)+" through "+name(end))
+		default:
+			parts = append(parts, name(rangePart))
+		}
+	}
+
+	description := joinWithAnd(parts)
+	if names == nil && valuesOnly {
+		if _, isSingle := cronSingleValue(field); isSingle {
+			return unit + " " + description
+		}
+		return unit + "s " + description
+	}
+
+	return description
+}
+
+// joinWithAnd joins the items like "a, b and c"
+func joinWithAnd(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// CronPreview is the upcoming schedule of a single cron trigger
+type CronPreview struct {
+	Component  string
+	Expression string
+	// The plain-language schedule, or the reason the expression is invalid
+	Description string
+	Invalid     bool
+	NextTimes   []time.Time
+}
+
+// previewCronTriggers computes the next fire times of every cron trigger, after the given time and in its location
+func previewCronTriggers(tomlData *SpinTOML, vars map[string]VariableValue, now time.Time, count int) []CronPreview {
+	var previews []CronPreview
+	for _, otherTrigger := range tomlData.Trigger.Other {
+		if otherTrigger.TriggerType != "cron" {
+			continue
+		}
+
+		preview := CronPreview{Component: otherTrigger.Component}
+		rawExpression, _ := otherTrigger.Config["cron_expression"].(string)
+		expression, err := resolveTemplate(rawExpression, vars)
+		if err != nil {
+			preview.Expression = rawExpression
+			preview.Description = "ERR: " + err.Error()
+			preview.Invalid = true
+			previews = append(previews, preview)
+			continue
+		}
+		preview.Expression = expression
+
+		schedule, err := parseCron(expression)
+		if err != nil {
+			preview.Description = "ERR: " + err.Error()
+			preview.Invalid = true
+		} else {
+			preview.Description = schedule.Describe()
+			preview.NextTimes = schedule.NextN(now, count)
+		}
+
+		previews = append(previews, preview)
+	}
+
+	return previews
+}
+
+// CronOverlap is an instant where several cron triggers fire at once
+type CronOverlap struct {
+	Time       time.Time
+	Components []string
+}
+
+// findCronOverlaps finds the instants where several triggers fire at once. Only the instants up to
+// the last previewed time of every trigger are compared, as the later fire times of some triggers aren't known.
+func findCronOverlaps(previews []CronPreview) []CronOverlap {
+	var horizon time.Time
+	for _, preview := range previews {
+		if len(preview.NextTimes) == 0 {
+			continue
+		}
+		last := preview.NextTimes[len(preview.NextTimes)-1]
+		if horizon.IsZero() || last.Before(horizon) {
+			horizon = last
+		}
+	}
+
+	// Keyed on the Unix time, as time.Time values of the same instant can differ in location.
+	// A component with several triggers firing at once doesn't overlap with itself, so it is listed once.
+	components := make(map[int64][]string)
+	instants := make(map[int64]time.Time)
+	for _, preview := range previews {
+		for _, next := range preview.NextTimes {
+			if !next.After(horizon) && !slices.Contains(components[next.Unix()], preview.Component) {
+				components[next.Unix()] = append(components[next.Unix()], preview.Component)
+				instants[next.Unix()] = next
+			}
+		}
+	}
+
+	var overlaps []CronOverlap
+	for instant, names := range components {
+		if len(names) > 1 {
+			sort.Strings(names)
+			overlaps = append(overlaps, CronOverlap{Time: instants[instant], Components: names})
+		}
+	}
+	sort.Slice(overlaps, func(i, j int) bool { return overlaps[i].Time.Before(overlaps[j].Time) })
+
+	return overlaps
+}
+
+// The layout the fire times are shown in
+const cronTimeLayout = "2006-01-02 15:04:05 MST"
+
+// showCronPreviews will display the schedule of every cron trigger, followed by the overlapping runs
+func showCronPreviews(previews []CronPreview, overlaps []CronOverlap) string {
+	if len(previews) == 0 {
+		return "\nNo cron triggers found\n"
+	}
+
+	previewTable := table.NewWriter()
+	previewTable.SetTitle("Cron Schedules")
+	previewTable.AppendHeader(table.Row{"component", "expression", "schedule", "next runs"})
+	for _, preview := range previews {
+		var nextTimes []string
+		for _, next := range preview.NextTimes {
+			nextTimes = append(nextTimes, next.Format(cronTimeLayout))
+		}
+		if !preview.Invalid && len(nextTimes) == 0 {
+			nextTimes = append(nextTimes, "never")
+		}
+		previewTable.AppendRow(table.Row{preview.Component, preview.Expression, preview.Description, strings.Join(nextTimes, "\n")})
+	}
+
+	outputString := "\n" + previewTable.Render() + "\n"
+
+	if len(overlaps) > 0 {
+		overlapTable := table.NewWriter()
+		overlapTable.SetTitle("Overlapping Runs")
+		overlapTable.AppendHeader(table.Row{"time", "components"})
+		for _, overlap := range overlaps {
+			overlapTable.AppendRow(table.Row{overlap.Time.Format(cronTimeLayout), strings.Join(overlap.Components, "\n")})
+		}
+		outputString += "\n" + overlapTable.Render() + "\n"
+	}
+
+	return outputString
+}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 2026-01-01 is a Thursday
+	newYear := time.Date(2026, time.January, 1, 0, 3, 10, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		expression string
+		after      time.Time
+		want       []time.Time
+	}{
+		{
+			name:       "every_5_minutes",
+			expression: "0 */5 * * * *",
+			after:      newYear,
+			want: []time.Time{
+				time.Date(2026, time.January, 1, 0, 5, 0, 0, time.UTC),
+				time.Date(2026, time.January, 1, 0, 10, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:       "every_20_seconds",
+			expression: "*/20 * * * * *",
+			after:      newYear,
+			want: []time.Time{
+				time.Date(2026, time.January, 1, 0, 3, 20, 0, time.UTC),
+				time.Date(2026, time.January, 1, 0, 3, 40, 0, time.UTC),
+			},
+		},
+		{
+			name:       "weekdays_skip_the_weekend",
+			expression: "0 0 9 * * MON-FRI",
+			after:      time.Date(2026, time.January, 2, 10, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2026, time.January, 5, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, time.January, 6, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:       "spin_sunday_is_1",
+			expression: "0 0 12 * * 1",
+			after:      newYear,
+			want:       []time.Time{time.Date(2026, time.January, 4, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:       "leap_day",
+			expression: "0 0 0 29 FEB *",
+			after:      newYear,
+			want:       []time.Time{time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:       "year_without_a_match",
+			expression: "0 0 0 29 2 * 2027",
+			after:      newYear,
+		},
+		{
+			// 02:30 doesn't exist on 2026-03-08 in New York, as the clocks go from 02:00 to 03:00
+			name:       "skips_the_dst_gap",
+			expression: "0 30 2 * * *",
+			after:      time.Date(2026, time.March, 7, 3, 0, 0, 0, newYork),
+			want:       []time.Time{time.Date(2026, time.March, 9, 2, 30, 0, 0, newYork)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseCron(tt.expression)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			count := len(tt.want)
+			if count == 0 {
+				count = 1
+			}
+			got := schedule.NextN(tt.after, count)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("NextN() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expression := range []string{
+		"* * *",
+		// Spin's cron trigger doesn't accept crontab expressions
+		"0 * * * *",
+		"60 * * * * *",
+		"0 0 5-1 * * *",
+		"0 */0 * * * *",
+		"0 0 0 * FOO *",
+		"0 0 0 * * 0",
+		"0 0 0 * * * 1969",
+	} {
+		if _, err := parseCron(expression); err == nil {
+			t.Errorf("expected an error for %q", expression)
+		}
+	}
+}
+
+func TestCronScheduleDescribe(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"* * * * * *", "every second"},
+		{"*/10 * * * * *", "every 10 seconds"},
+		{"0 * * * * *", "every minute"},
+		{"0 */5 * * * *", "every 5 minutes"},
+		{"0 0 * * * *", "every hour"},
+		{"0 15 * * * *", "every hour at minute 15"},
+		{"0 0,30 * * * *", "every hour at minutes 0 and 30"},
+		{"0 0 */6 * * *", "every 6 hours"},
+		{"0 0 9 * * MON-FRI", "at 09:00 on Monday through Friday"},
+		{"0 0 9,17 * * *", "at 09:00 and 17:00"},
+		{"0 30 2 1 JAN,JUL *", "at 02:30 on day 1 of the month in January and July"},
+		{"0 0 12 * * 1", "at 12:00 on Sunday"},
+		{"0 0-30/10 9-17 * * *", "every 10 minutes from 0 through 30, hours 9 through 17"},
+		{"0 0 0 1,15 * ? 2030", "at 00:00 on days 1 and 15 of the month in year 2030"},
+	}
+
+	for _, tt := range tests {
+		schedule, err := parseCron(tt.expression)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", tt.expression, err)
+		}
+
+		if got := schedule.Describe(); got != tt.want {
+			t.Errorf("Describe(%q) = %q, want %q", tt.expression, got, tt.want)
+		}
+	}
+}
+
+func TestFindCronOverlaps(t *testing.T) {
+	tomlData := &SpinTOML{
+		Variables: map[string]Variable{"report_schedule": {Default: "0 */30 * * * *"}},
+		Trigger: Trigger{Other: []OtherTrigger{
+			{Component: "cleanup", TriggerType: "cron", Config: map[string]any{"cron_expression": "0 */15 * * * *"}},
+			{Component: "report", TriggerType: "cron", Config: map[string]any{"cron_expression": "{{ report_schedule }}"}},
+			{Component: "broken", TriggerType: "cron", Config: map[string]any{"cron_expression": "not cron"}},
+			{Component: "other", TriggerType: "sqs", Config: map[string]any{"queue_url": "https://example.com"}},
+		}},
+	}
+
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	previews := previewCronTriggers(tomlData, resolveVariables(tomlData, nil), now, 4)
+
+	if len(previews) != 3 {
+		t.Fatalf("expected 3 cron previews, got %d", len(previews))
+	}
+	if previews[1].Expression != "0 */30 * * * *" || previews[1].Description != "every 30 minutes" {
+		t.Errorf("expected the templated expression to be resolved, got %+v", previews[1])
+	}
+	if !previews[2].Invalid {
+		t.Errorf("expected the broken expression to be invalid, got %+v", previews[2])
+	}
+
+	// Cleanup runs at 00:15, 00:30, 00:45 and 01:00, so only the runs up to 01:00 are compared
+	want := []CronOverlap{
+		{Time: time.Date(2026, time.January, 1, 0, 30, 0, 0, time.UTC), Components: []string{"cleanup", "report"}},
+		{Time: time.Date(2026, time.January, 1, 1, 0, 0, 0, time.UTC), Components: []string{"cleanup", "report"}},
+	}
+	if diff := cmp.Diff(want, findCronOverlaps(previews)); diff != "" {
+		t.Errorf("findCronOverlaps() mismatch (-want +got):\n%s", diff)
+	}
+
+	t.Run("same_component", func(t *testing.T) {
+		// Two triggers of one component firing at once are not an overlap, and the component is only listed once
+		tomlData := &SpinTOML{Trigger: Trigger{Other: []OtherTrigger{
+			{Component: "cleanup", TriggerType: "cron", Config: map[string]any{"cron_expression": "0 */15 * * * *"}},
+			{Component: "cleanup", TriggerType: "cron", Config: map[string]any{"cron_expression": "0 */30 * * * *"}},
+			{Component: "report", TriggerType: "cron", Config: map[string]any{"cron_expression": "0 0 * * * *"}},
+		}}}
+		previews := previewCronTriggers(tomlData, resolveVariables(tomlData, nil), now, 4)
+
+		want := []CronOverlap{
+			{Time: time.Date(2026, time.January, 1, 1, 0, 0, 0, time.UTC), Components: []string{"cleanup", "report"}},
+		}
+		if diff := cmp.Diff(want, findCronOverlaps(previews)); diff != "" {
+			t.Errorf("findCronOverlaps() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/list"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var depsCmd = &cobra.Command{
+	Use:   "deps",
+	Short: "Display the dependencies composed into every component",
+	Long: `The "deps" command reads a spin.toml file and shows the "[component.<name>.dependencies]" of every component
+as a tree, whether they are registry packages, local Wasm files or HTTP URLs.
+Dependencies shared by several components are also listed, to show which libraries the application relies on most.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tomlData, _, err := loadApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(showDependencies(tomlData))
+		return nil
+	},
+}
+
+// describeDependency returns where the dependency comes from, e.g. "aws:client@1.0.0 from registry.example.com"
+func describeDependency(importName string, dependency Dependency) string {
+	var description string
+	switch {
+	case dependency.Path != "":
+		description = "local file " + dependency.Path
+	case dependency.URL != "":
+		description = dependency.URL + " (" + dependency.Digest + ")"
+	default:
+		packageName := dependency.Package
+		if packageName == "" {
+			// Without a package, the import names the package (minus any interface path)
+			packageName, _, _ = strings.Cut(importName, "/")
+		}
+		description = packageName + "@" + dependency.Version
+		if dependency.Registry != "" {
+			description += " from " + dependency.Registry
+		}
+	}
+
+	if dependency.Export != "" {
+		description += ", export " + dependency.Export
+	}
+
+	return description
+}
+
+// appendDependencyItems adds the dependencies of the component to the list, at the current indentation
+func appendDependencyItems(dependencyList list.Writer, componentData Component) {
+	for _, importName := range sortedKeys(componentData.Dependencies) {
+		dependency := componentData.Dependencies[importName]
+		item := importName + " <- " + describeDependency(importName, dependency)
+		for _, key := range dependency.UnknownKeys {
+			item += fmt.Sprintf(" (WARN: unknown setting %q)", key)
+		}
+		dependencyList.AppendItem(item)
+	}
+}
+
+// dependencyTreeRoot labels the component at the root of its dependency tree
+func dependencyTreeRoot(componentName string, componentData Component) string {
+	if componentData.DependenciesInheritConfiguration {
+		return componentName + " (dependencies inherit configuration)"
+	}
+
+	return componentName
+}
+
+// showComponentDependencies will display the dependency tree of a single component
+func showComponentDependencies(componentName string, componentData Component) string {
+	dependencyList := list.NewWriter()
+	dependencyList.SetStyle(list.StyleConnectedRounded)
+
+	dependencyList.AppendItem(dependencyTreeRoot(componentName, componentData))
+	dependencyList.Indent()
+	appendDependencyItems(dependencyList, componentData)
+
+	return dependencyList.Render()
+}
+
+// showDependencies will display the dependency tree of every component, followed by the dependencies used by several components
+func showDependencies(tomlData *SpinTOML) string {
+	var componentNames []string
+	for _, name := range sortedKeys(tomlData.Component) {
+		if len(tomlData.Component[name].Dependencies) > 0 {
+			componentNames = append(componentNames, name)
+		}
+	}
+
+	if len(componentNames) == 0 {
+		return "\nNo component has dependencies\n"
+	}
+
+	dependencyList := list.NewWriter()
+	dependencyList.SetStyle(list.StyleConnectedRounded)
+	dependencyList.AppendItem(tomlData.Application.Name)
+	dependencyList.Indent()
+
+	// The components using each dependency, keyed by its description
+	users := make(map[string][]string)
+	for _, name := range componentNames {
+		componentData := tomlData.Component[name]
+
+		dependencyList.AppendItem(dependencyTreeRoot(name, componentData))
+		dependencyList.Indent()
+		appendDependencyItems(dependencyList, componentData)
+		dependencyList.UnIndent()
+
+		for importName, dependency := range componentData.Dependencies {
+			description := describeDependency(importName, dependency)
+			users[description] = append(users[description], name)
+		}
+	}
+
+	outputString := "\n" + dependencyList.Render() + "\n"
+
+	var shared []string
+	for description, components := range users {
+		if len(components) > 1 {
+			shared = append(shared, description)
+		}
+	}
+	sort.Strings(shared)
+
+	if len(shared) > 0 {
+		sharedTable := table.NewWriter()
+		sharedTable.SetTitle("Shared Dependencies")
+		sharedTable.AppendHeader(table.Row{"dependency", "components"})
+		for _, description := range shared {
+			sort.Strings(users[description])
+			sharedTable.AppendRow(table.Row{description, strings.Join(users[description], "\n")})
+		}
+		outputString += "\n" + sharedTable.Render() + "\n"
+	}
+
+	return outputString
+}
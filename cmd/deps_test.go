@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseDependencies(t *testing.T) {
+	tomlData, err := parseSpinToml("../test_data/spin_deps.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	storage := tomlData.Component["storage"]
+	if !storage.DependenciesInheritConfiguration {
+		t.Errorf("expected the storage dependencies to inherit configuration")
+	}
+
+	want := map[string]Dependency{
+		"aws:client/s3":      {Version: "1.0.0"},
+		"blueprint:logging":  {Version: "^0.2", Registry: "registry.example.com", Package: "shared:logging"},
+		"blueprint:cache/kv": {Path: "deps/cache.wasm"},
+	}
+	if diff := cmp.Diff(want, storage.Dependencies); diff != "" {
+		t.Errorf("parseSpinToml() dependencies mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseInvalidDependencies(t *testing.T) {
+	tests := []struct {
+		name       string
+		dependency string
+	}{
+		{name: "no_source", dependency: `{ export = "a:b" }`},
+		{name: "several_sources", dependency: `{ version = "1.0.0", path = "dep.wasm" }`},
+		{name: "url_without_digest", dependency: `{ url = "https://example.com/dep.wasm" }`},
+		{name: "wrong_type", dependency: `1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var component Component
+			if _, err := toml.Decode("[dependencies]\n\"a:b\" = "+tt.dependency, &component); err == nil {
+				t.Errorf("expected an error for %s", tt.dependency)
+			}
+		})
+	}
+}
+
+func TestParseDependencyUnknownKeys(t *testing.T) {
+	var component Component
+	if _, err := toml.Decode("[dependencies]\n\"a:b\" = { path = \"dep.wasm\", checksum = \"abc\", alias = \"c\" }", &component); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Dependency{Path: "dep.wasm", UnknownKeys: []string{"alias", "checksum"}}
+	if diff := cmp.Diff(want, component.Dependencies["a:b"]); diff != "" {
+		t.Errorf("Dependency.UnmarshalTOML() mismatch (-want +got):\n%s", diff)
+	}
+
+	tomlData := &SpinTOML{Component: map[string]Component{"api": component}}
+	wantFindings := []LintFinding{
+		{Check: "dependencies", Subject: "component api dependency a:b", Message: `unknown setting "alias"`},
+		{Check: "dependencies", Subject: "component api dependency a:b", Message: `unknown setting "checksum"`},
+	}
+	if diff := cmp.Diff(wantFindings, lintApp(tomlData, nil)); diff != "" {
+		t.Errorf("lintApp() mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := showComponentDependencies("api", component); !strings.Contains(got, `(WARN: unknown setting "checksum")`) {
+		t.Errorf("expected the unknown setting to be shown, got:\n%s", got)
+	}
+}
+
+func TestShowDependencies(t *testing.T) {
+	tomlData, err := parseSpinToml("../test_data/spin_deps.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := showDependencies(tomlData)
+	for _, want := range []string{
+		"storage (dependencies inherit configuration)",
+		"aws:client/s3 <- aws:client@1.0.0",
+		"blueprint:cache/kv <- local file deps/cache.wasm",
+		"blueprint:pdf <- https://example.com/pdf.wasm (sha256:0123456789abcdef), export pdf:render",
+		"| shared:logging@^0.2 from registry.example.com | reports    |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the output to contain %q, got:\n%s", want, got)
+		}
+	}
+
+	// Components without dependencies are left out
+	if strings.Contains(got, "static") {
+		t.Errorf("expected the static component to be left out, got:\n%s", got)
+	}
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// dotenvEntry is a single key/value pair read from a ".env" file
+type dotenvEntry struct {
+	Key   string
+	Value string
+	// The line the entry starts on (values in quotes can span several lines)
+	Line int
+}
+
+// parseDotenv reads every entry from a ".env" file, in the order they appear. It supports:
+//   - comments, both on their own line and after a value ("KEY=value # comment")
+//   - an optional "export " prefix before the key
+//   - single-quoted values, which are taken literally and can span several lines
+//   - double-quoted values, which can span several lines and support escapes ("\n", "\t", "\"", "\\" and "\$")
+//   - "${VAR}" interpolation in unquoted and double-quoted values
+//
+// Interpolated names are looked up in the entries read so far (the last one wins), and then using lookupEnv.
+func parseDotenv(r io.Reader, lookupEnv func(string) (string, bool)) ([]dotenvEntry, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var entries []dotenvEntry
+	values := make(map[string]string)
+	lookup := func(name string) (string, bool) {
+		if value, ok := values[name]; ok {
+			return value, true
+		}
+		return lookupEnv(name)
+	}
+
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimSpace(lines[i])
+		// Ignore comments and empty lines
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		key, rest, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=value, got %q", lineNumber, line)
+		}
+
+		key = strings.TrimSpace(key)
+		if !isDotenvKey(key) {
+			return nil, fmt.Errorf("line %d: invalid key %q", lineNumber, key)
+		}
+
+		// Remembering whether the value starts with whitespace, as that makes a leading "#" a comment
+		spaced := strings.HasPrefix(rest, " ") || strings.HasPrefix(rest, "\t")
+		rest = strings.TrimLeft(rest, " \t")
+		var value string
+		var err error
+		switch {
+		case strings.HasPrefix(rest, "'") || strings.HasPrefix(rest, `"`):
+			quote := rest[0]
+			// Quoted values continue onto the following lines until the closing quote is found
+			var raw string
+			var remainder string
+			raw, remainder, i, err = readQuotedValue(lines, i, rest[1:], quote)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+
+			remainder = strings.TrimSpace(remainder)
+			if remainder != "" && !strings.HasPrefix(remainder, "#") {
+				return nil, fmt.Errorf("line %d: unexpected characters after the closing quote: %q", lineNumber, remainder)
+			}
+
+			if quote == '\'' {
+				value = raw
+			} else {
+				value, err = expandDotenvValue(raw, true, lookup)
+			}
+		default:
+			// An inline comment must be preceded by whitespace, so values like "color=#fff" are kept intact
+			if spaced && strings.HasPrefix(rest, "#") {
+				rest = ""
+			} else if idx := strings.Index(rest, " #"); idx >= 0 {
+				rest = rest[:idx]
+			} else if idx := strings.Index(rest, "\t#"); idx >= 0 {
+				rest = rest[:idx]
+			}
+			value, err = expandDotenvValue(strings.TrimSpace(rest), false, lookup)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		values[key] = value
+		entries = append(entries, dotenvEntry{Key: key, Value: value, Line: lineNumber})
+	}
+
+	return entries, nil
+}
+
+// readQuotedValue reads a quoted value that starts on lines[start] and may continue onto the following lines.
+// It returns the raw value (escapes are left in place), whatever follows the closing quote and the index of the last line read.
+func readQuotedValue(lines []string, start int, rest string, quote byte) (string, string, int, error) {
+	var sb strings.Builder
+	current := rest
+	for i := start; i < len(lines); i++ {
+		if i > start {
+			sb.WriteByte('\n')
+			current = lines[i]
+		}
+
+		for j := 0; j < len(current); j++ {
+			switch {
+			case current[j] == '\\' && quote == '"' && j+1 < len(current):
+				// Keeping the escape for expandDotenvValue, but skipping the escaped character so it can't close the value
+				sb.WriteByte(current[j])
+				sb.WriteByte(current[j+1])
+				j++
+			case current[j] == quote:
+				return sb.String(), current[j+1:], i, nil
+			default:
+				sb.WriteByte(current[j])
+			}
+		}
+	}
+
+	return "", "", 0, fmt.Errorf("missing closing quote (%c)", quote)
+}
+
+// expandDotenvValue interpolates "${VAR}" references, and also handles escapes if the value was double-quoted
+func expandDotenvValue(raw string, escapes bool, lookup func(string) (string, bool)) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case escapes && c == '\\' && i+1 < len(raw):
+			i++
+			switch raw[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case '\\', '"', '$':
+				sb.WriteByte(raw[i])
+			default:
+				// Unknown escapes are kept as they are
+				sb.WriteByte('\\')
+				sb.WriteByte(raw[i])
+			}
+		case c == '$' && strings.HasPrefix(raw[i:], "${"):
+			end := strings.IndexByte(raw[i:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated variable reference %q", raw[i:])
+			}
+
+			name := raw[i+2 : i+end]
+			if !isDotenvKey(name) {
+				return "", fmt.Errorf("invalid variable reference %q", raw[i:i+end+1])
+			}
+
+			// Undefined variables expand to an empty string, like in a shell
+			value, _ := lookup(name)
+			sb.WriteString(value)
+			i += end
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// isDotenvKey reports whether the string is a valid env var name
+func isDotenvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for i, c := range key {
+		isLetter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && !(isDigit && i > 0) && !(c == '.' && i > 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// readDotenvFile reads every entry from the ".env" file at the path
+func readDotenvFile(filePath string) ([]dotenvEntry, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries, err := parseDotenv(file, os.LookupEnv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", filePath, err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseDotenv(t *testing.T) {
+	lookupEnv := func(name string) (string, bool) {
+		if name == "HOME" {
+			return "/home/spin", true
+		}
+		return "", false
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []dotenvEntry
+	}{
+		{
+			name:    "comments_and_export",
+			content: "# A comment\n\nexport FOO=bar\nBAZ = qux # inline comment\nCOLOR=#fff\nEMPTY= # nothing here",
+			want: []dotenvEntry{
+				{Key: "FOO", Value: "bar", Line: 3},
+				{Key: "BAZ", Value: "qux", Line: 4},
+				{Key: "COLOR", Value: "#fff", Line: 5},
+				{Key: "EMPTY", Value: "", Line: 6},
+			},
+		},
+		{
+			name:    "quotes_and_escapes",
+			content: `SINGLE='${HOME} \n # kept'` + "\n" + `DOUBLE="line\tone\n\"two\" \$HOME" # comment`,
+			want: []dotenvEntry{
+				{Key: "SINGLE", Value: `${HOME} \n # kept`, Line: 1},
+				{Key: "DOUBLE", Value: "line\tone\n\"two\" $HOME", Line: 2},
+			},
+		},
+		{
+			name:    "multi_line_values",
+			content: "CERT=\"-----BEGIN-----\nabc\n-----END-----\"\nNEXT='a\nb'\nAFTER=1",
+			want: []dotenvEntry{
+				{Key: "CERT", Value: "-----BEGIN-----\nabc\n-----END-----", Line: 1},
+				{Key: "NEXT", Value: "a\nb", Line: 4},
+				{Key: "AFTER", Value: "1", Line: 6},
+			},
+		},
+		{
+			name:    "interpolation",
+			content: "HOST=localhost\nURL=http://${HOST}:3000\nQUOTED=\"${HOME}/data\"\nMISSING=${NOPE}x\nHOST=example.com\nURL2=${HOST}",
+			want: []dotenvEntry{
+				{Key: "HOST", Value: "localhost", Line: 1},
+				{Key: "URL", Value: "http://localhost:3000", Line: 2},
+				{Key: "QUOTED", Value: "/home/spin/data", Line: 3},
+				{Key: "MISSING", Value: "x", Line: 4},
+				{Key: "HOST", Value: "example.com", Line: 5},
+				{Key: "URL2", Value: "example.com", Line: 6},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDotenv(strings.NewReader(tt.content), lookupEnv)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseDotenv() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseDotenvErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "missing_equals", content: "FOO", wantErr: "line 1: expected KEY=value"},
+		{name: "invalid_key", content: "\n1FOO=bar", wantErr: "line 2: invalid key"},
+		{name: "unclosed_quote", content: "FOO=\"bar\nBAZ=qux", wantErr: "line 1: missing closing quote"},
+		{name: "trailing_characters", content: "FOO='bar' baz", wantErr: "line 1: unexpected characters"},
+		{name: "unterminated_reference", content: "FOO=${BAR", wantErr: "line 1: unterminated variable reference"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseDotenv(strings.NewReader(tt.content), func(string) (string, bool) { return "", false })
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParseEnvVarsDuplicates(t *testing.T) {
+	// The file name intentionally doesn't end with ".env"
+	got, err := parseEnvVars("../test_data/duplicates.env.example")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := VariableValue{
+		Value:  "third",
+		Source: VariableSource{Kind: SourceDotenv, File: "../test_data/duplicates.env.example", Line: 6},
+	}
+	if diff := cmp.Diff(want, got["test_var"]); diff != "" {
+		t.Errorf("parseEnvVars() mismatch (-want +got):\n%s", diff)
+	}
+}
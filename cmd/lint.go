@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check a Spin application for configuration problems",
+	Long: `The "lint" command reads a spin.toml file and the Spin variables, then reports every configuration problem found.
+The command exits with a non-zero status if any problem is found.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tomlData, envVars, err := loadApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		findings := lintApp(tomlData, envVars)
+		fmt.Print(showLintFindings(findings))
+
+		if len(findings) > 0 {
+			return findingsError(cmd, "%d problem(s) found", len(findings))
+		}
+
+		return nil
+	},
+}
+
+// LintFinding is a single configuration problem found by the "lint" command
+type LintFinding struct {
+	// The name of the check that found the problem
+	Check string
+	// What the problem is about, e.g. a variable or a component
+	Subject string
+	Message string
+}
+
+// lintApp runs every check against the application
+func lintApp(tomlData *SpinTOML, envVars map[string]VariableValue) []LintFinding {
+	var findings []LintFinding
+
+	for _, violation := range validateVariableConstraints(tomlData, resolveVariables(tomlData, envVars)) {
+		findings = append(findings, LintFinding{Check: "variable-constraint", Subject: violation.Variable, Message: violation.Message})
+	}
+
+	// The settings are checked with the real values, but secrets are masked in the messages
+	values := resolveVariables(tomlData, envVars)
+	shownValues := maskSecrets(tomlData, values)
+	for _, otherTrigger := range tomlData.Trigger.Other {
+		subject := fmt.Sprintf("trigger.%s (component %s)", otherTrigger.TriggerType, otherTrigger.Component)
+		for _, problem := range validateTriggerSettings(otherTrigger, tomlData.Application.Trigger.Other[otherTrigger.TriggerType], values, shownValues) {
+			findings = append(findings, LintFinding{Check: "trigger-settings", Subject: subject, Message: problem})
+		}
+	}
+
+	for _, name := range sortedKeys(tomlData.Component) {
+		componentData := tomlData.Component[name]
+		for _, importName := range sortedKeys(componentData.Dependencies) {
+			subject := fmt.Sprintf("component %s dependency %s", name, importName)
+			for _, key := range componentData.Dependencies[importName].UnknownKeys {
+				findings = append(findings, LintFinding{Check: "dependencies", Subject: subject, Message: fmt.Sprintf("unknown setting %q", key)})
+			}
+		}
+	}
+
+	return findings
+}
+
+// showLintFindings will display a table with every lint finding
+func showLintFindings(findings []LintFinding) string {
+	if len(findings) == 0 {
+		return "\nNo problems found\n"
+	}
+
+	findingTable := table.NewWriter()
+	findingTable.SetTitle("Lint Findings")
+	findingTable.AppendHeader(table.Row{"check", "subject", "problem"})
+	for _, finding := range findings {
+		findingTable.AppendRow(table.Row{finding.Check, finding.Subject, finding.Message})
+	}
+
+	return "\n" + findingTable.Render() + "\n"
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// manifestVersion is used to detect the version of a "spin.toml" file before decoding the rest of it
+type manifestVersion struct {
+	SpinManifestVersion any `toml:"spin_manifest_version"`
+	// Very old manifests use "spin_version" instead
+	SpinVersion any `toml:"spin_version"`
+}
+
+// isV1 reports whether the manifest uses the legacy version 1 format, where the version is the string "1"
+func (v manifestVersion) isV1() bool {
+	for _, version := range []any{v.SpinManifestVersion, v.SpinVersion} {
+		if fmt.Sprint(version) == "1" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SpinTOMLV1 is a legacy version 1 "spin.toml" file
+type SpinTOMLV1 struct {
+	Name        string              `toml:"name"`
+	Version     string              `toml:"version"`
+	Authors     []string            `toml:"authors"`
+	Description string              `toml:"description"`
+	Trigger     TriggerV1           `toml:"trigger"`
+	Variables   map[string]Variable `toml:"variables"`
+	Component   []ComponentV1       `toml:"component"`
+}
+
+// TriggerV1 is the application-wide trigger. In version 1, every component shares a single trigger type.
+type TriggerV1 struct {
+	Type    string `toml:"type"`
+	Base    string `toml:"base"`
+	Address string `toml:"address"`
+}
+
+type ComponentV1 struct {
+	ID               string            `toml:"id"`
+	Description      string            `toml:"description"`
+	Source           Source            `toml:"source"`
+	AllowedHTTPHosts []string          `toml:"allowed_http_hosts"`
+	KeyValueStores   []string          `toml:"key_value_stores"`
+	AIModels         []string          `toml:"ai_models"`
+	SQLiteDatabases  []string          `toml:"sqlite_databases"`
+	Config           map[string]string `toml:"config"`
+	Environment      map[string]string `toml:"environment"`
+	// The settings depend on the application trigger type, e.g. "route" for HTTP or "channel" for Redis
+	Trigger map[string]any `toml:"trigger"`
+}
+
+// parseSpinTomlV1 decodes a version 1 manifest. It also returns the keys that are not part of
+// the model (e.g. "component.build"), as they would be lost when migrating the manifest.
+func parseSpinTomlV1(filePath string) (*SpinTOMLV1, []string, error) {
+	var tomlFile SpinTOMLV1
+	md, err := toml.DecodeFile(filePath, &tomlFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var undecoded []string
+	seen := make(map[string]bool)
+	for _, key := range md.Undecoded() {
+		// The version and the keys inside "source" and the component trigger are read by custom decoding, which the metadata can't see
+		if key[0] == "spin_manifest_version" || key[0] == "spin_version" ||
+			(key[0] == "component" && len(key) > 2 && (key[1] == "source" || key[1] == "trigger")) {
+			continue
+		}
+
+		// Only reporting the outermost key of an undecoded table, rather than every key inside it
+		if len(key) > 1 && seen[key[:len(key)-1].String()] {
+			seen[key.String()] = true
+			continue
+		}
+		seen[key.String()] = true
+		if !slices.Contains(undecoded, key.String()) {
+			undecoded = append(undecoded, key.String())
+		}
+	}
+
+	return &tomlFile, undecoded, nil
+}
+
+// convertV1 maps a version 1 manifest into the version 2 model, so every view works unchanged.
+// It also returns notes about the constructs whose meaning changed, which need human attention when migrating.
+func convertV1(v1 *SpinTOMLV1) (*SpinTOML, []string, error) {
+	var notes []string
+
+	tomlData := &SpinTOML{
+		Application: Application{
+			Name:        v1.Name,
+			Version:     v1.Version,
+			Authors:     v1.Authors,
+			Description: v1.Description,
+		},
+		Variables: v1.Variables,
+		Component: make(map[string]Component, len(v1.Component)),
+	}
+
+	switch v1.Trigger.Type {
+	case "http":
+		tomlData.Application.Trigger.HTTP.Base = strings.TrimSuffix(v1.Trigger.Base, "/")
+	case "redis":
+		tomlData.Application.Trigger.Redis.Address = v1.Trigger.Address
+	}
+
+	for i, componentV1 := range v1.Component {
+		if componentV1.ID == "" {
+			return nil, nil, fmt.Errorf("component #%d has no id", i+1)
+		}
+		if _, exists := tomlData.Component[componentV1.ID]; exists {
+			return nil, nil, fmt.Errorf("duplicate component id %q", componentV1.ID)
+		}
+
+		for _, host := range componentV1.AllowedHTTPHosts {
+			if host == "insecure:allow-all" {
+				notes = append(notes, fmt.Sprintf("component %q: \"insecure:allow-all\" became \"*://*:*\", which also allows database and Redis connections to any host", componentV1.ID))
+			} else if !strings.Contains(host, "://") {
+				notes = append(notes, fmt.Sprintf("component %q: the allowed host %q has no scheme, so it was allowed over both HTTP and HTTPS; remove the one you don't need", componentV1.ID, host))
+			}
+		}
+
+		tomlData.Component[componentV1.ID] = Component{
+			Description:          componentV1.Description,
+			Source:               componentV1.Source,
+			Variables:            componentV1.Config,
+			AllowedOutboundHosts: convertAllowedHTTPHosts(componentV1.AllowedHTTPHosts),
+			KeyValueStores:       componentV1.KeyValueStores,
+			AIModels:             componentV1.AIModels,
+			SQLiteDatabases:      componentV1.SQLiteDatabases,
+			Environment:          componentV1.Environment,
+		}
+
+		for key := range componentV1.Trigger {
+			// The settings of plugin triggers are all kept as they are
+			knownKeys, ok := knownTriggerKeysV1[v1.Trigger.Type]
+			if ok && !slices.Contains(knownKeys, key) {
+				notes = append(notes, fmt.Sprintf("component %q: the trigger setting %q is not migrated", componentV1.ID, key))
+			}
+		}
+
+		switch v1.Trigger.Type {
+		case "http":
+			route, _ := componentV1.Trigger["route"].(string)
+			httpTrigger := HTTPTrigger{Route: Route{String: route}, Component: componentV1.ID}
+			if executor, ok := componentV1.Trigger["executor"].(map[string]any); ok {
+				httpTrigger.Executor.Type, _ = executor["type"].(string)
+				httpTrigger.Executor.Entrypoint, _ = executor["entrypoint"].(string)
+				httpTrigger.Executor.Argv, _ = executor["argv"].(string)
+				for key := range executor {
+					if !slices.Contains([]string{"type", "entrypoint", "argv"}, key) {
+						notes = append(notes, fmt.Sprintf("component %q: the executor setting %q is not migrated", componentV1.ID, key))
+					}
+				}
+			}
+			tomlData.Trigger.HTTP = append(tomlData.Trigger.HTTP, httpTrigger)
+		case "redis":
+			channel, _ := componentV1.Trigger["channel"].(string)
+			tomlData.Trigger.Redis = append(tomlData.Trigger.Redis, RedisTrigger{Channel: channel, Component: componentV1.ID})
+		default:
+			tomlData.Trigger.Other = append(tomlData.Trigger.Other, OtherTrigger{Component: componentV1.ID, TriggerType: v1.Trigger.Type, Config: componentV1.Trigger})
+		}
+	}
+
+	switch v1.Trigger.Type {
+	case "http", "redis":
+	default:
+		notes = append(notes, fmt.Sprintf("trigger type %q: check the trigger plugin's documentation for its version 2 settings", v1.Trigger.Type))
+	}
+
+	// Sorting the notes, so they are grouped by component
+	slices.Sort(notes)
+
+	return tomlData, notes, nil
+}
+
+// The component trigger settings that convertV1 maps, for each trigger type
+var knownTriggerKeysV1 = map[string][]string{
+	"http":  {"route", "executor"},
+	"redis": {"channel"},
+}
+
+// convertAllowedHTTPHosts maps version 1 "allowed_http_hosts" to version 2 "allowed_outbound_hosts", like Spin does.
+// Hosts without a scheme were reachable over both HTTP and HTTPS in version 1.
+func convertAllowedHTTPHosts(allowedHTTPHosts []string) []string {
+	var outboundHosts []string
+	for _, host := range allowedHTTPHosts {
+		switch {
+		case host == "insecure:allow-all":
+			outboundHosts = append(outboundHosts, "*://*:*")
+		case strings.Contains(host, "://"):
+			outboundHosts = append(outboundHosts, strings.TrimSuffix(host, "/"))
+		default:
+			host = strings.TrimSuffix(host, "/")
+			outboundHosts = append(outboundHosts, "http://"+host, "https://"+host)
+		}
+	}
+
+	return outboundHosts
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseSpinTOMLV1(t *testing.T) {
+	want := SpinTOML{
+		Application: Application{
+			Name:        "Test Spin TOML v1",
+			Version:     "0.1.0",
+			Authors:     []string{"Fermyon Engineering Team <engineering@fermyon.com>"},
+			Description: "This is a test version 1 spin.toml file used for testing the blueprint plugin.",
+			Trigger: ApplicationTrigger{
+				HTTP: ApplicationTriggerHTTP{Base: "/blueprint"},
+			},
+		},
+		Variables: map[string]Variable{
+			"test_var": {Default: "test"},
+		},
+		Trigger: Trigger{
+			HTTP: []HTTPTrigger{
+				{Route: Route{String: "/route-one/..."}, Component: "number-one"},
+				{Route: Route{String: "/route-two"}, Component: "number-two", Executor: Executor{Type: "wagi", Entrypoint: "_start", Argv: "${SCRIPT_NAME} ${ARGS}"}},
+			},
+		},
+		Component: map[string]Component{
+			"number-one": {
+				Description: "This is a description for component 1.",
+				Source:      Source{String: "component-one/main.wasm"},
+				Variables: map[string]string{
+					"parsed_test_var": "This is the test_var: {{ test_var }}",
+				},
+				AllowedOutboundHosts: []string{"http://example.com", "https://example.com", "https://api.example.com:8080", "*://*:*"},
+				KeyValueStores:       []string{"default"},
+				Environment:          map[string]string{"GREETING": "hello"},
+			},
+			"number-two": {
+				Source: Source{Struct: &struct {
+					URL    string `toml:"url"`
+					Digest string `toml:"digest"`
+				}{
+					URL:    "https://ghcr.io/fermyon/component-number-two",
+					Digest: "thisisatestdigeststring",
+				}},
+			},
+		},
+	}
+
+	got, err := parseSpinToml("../test_data/spin_v1.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := cmp.Diff(want, *got, cmpopts.IgnoreUnexported(Route{}, Source{})); diff != "" {
+		t.Errorf("parseSpinToml() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestConvertV1RedisTrigger(t *testing.T) {
+	v1 := &SpinTOMLV1{
+		Trigger: TriggerV1{Type: "redis", Address: "redis://localhost:6379"},
+		Component: []ComponentV1{
+			{ID: "listener", Trigger: map[string]any{"channel": "messages"}},
+		},
+	}
+
+	got, _, err := convertV1(v1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Application.Trigger.Redis.Address != "redis://localhost:6379" {
+		t.Errorf("expected the application Redis address to be kept, got %q", got.Application.Trigger.Redis.Address)
+	}
+
+	want := []RedisTrigger{{Channel: "messages", Component: "listener"}}
+	if diff := cmp.Diff(want, got.Trigger.Redis); diff != "" {
+		t.Errorf("convertV1() Redis triggers mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,407 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Convert a version 1 spin.toml file into a version 2 manifest",
+	Long: `The "migrate" command converts a version 1 spin.toml file into the version 2 format.
+Components are written as "[component.<id>]" tables with their own "[[trigger.<type>]]" entries,
+"config" becomes "variables" and "allowed_http_hosts" becomes "allowed_outbound_hosts" with explicit schemes.
+The command prints a diff of the changes and a list of the constructs that need human attention.
+Use "--output" to write the version 2 manifest to a file.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := manifestPath(cmd)
+		if err != nil {
+			return err
+		}
+
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return err
+		}
+
+		original, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		migrated, notes, err := migrateV1(path)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(unifiedDiff(path, path+" (version 2)", string(original), migrated))
+		fmt.Print(showMigrationNotes(notes))
+
+		if output == "" {
+			return nil
+		}
+
+		if _, err := os.Stat(output); err == nil && !force {
+			return fmt.Errorf("the file %q already exists, use \"--force\" to overwrite it", output)
+		}
+
+		if err := os.WriteFile(output, []byte(migrated), 0o644); err != nil {
+			return err
+		}
+
+		fmt.Printf("\nWrote the version 2 manifest to %q\n", output)
+		return nil
+	},
+}
+
+func init() {
+	migrateCmd.Flags().StringP("output", "o", "", "The file to write the version 2 manifest to. Nothing is written if omitted")
+	migrateCmd.Flags().Bool("force", false, "Overwrite the output file if it already exists")
+}
+
+// migrateV1 converts the version 1 manifest at the path into a version 2 manifest.
+// It returns the new manifest and notes about the constructs that need human attention.
+func migrateV1(filePath string) (string, []string, error) {
+	var version manifestVersion
+	if _, err := toml.DecodeFile(filePath, &version); err != nil {
+		return "", nil, err
+	}
+
+	if !version.isV1() {
+		return "", nil, fmt.Errorf("%q is not a version 1 manifest", filePath)
+	}
+
+	v1, undecoded, err := parseSpinTomlV1(filePath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tomlData, notes, err := convertV1(v1)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, key := range undecoded {
+		notes = append(notes, fmt.Sprintf("the setting %q is not migrated; copy it to the version 2 component by hand", key))
+	}
+
+	if len(v1.Component) > 0 {
+		// Spin 1 didn't restrict the hosts of outbound Redis and database connections, so nothing in the manifest says which are used
+		notes = append(notes, "version 1 allowed Redis, PostgreSQL and MySQL connections to any host; add the hosts each component connects to its allowed_outbound_hosts")
+	}
+
+	return renderManifestV2(tomlData), notes, nil
+}
+
+// Keys made of these characters don't need to be quoted in TOML
+var bareTOMLKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tomlKey formats a key, quoting it if needed
+func tomlKey(key string) string {
+	if bareTOMLKey.MatchString(key) {
+		return key
+	}
+
+	return tomlValue(key)
+}
+
+// tomlValue formats a value the way the toml package would, so strings are escaped correctly
+func tomlValue(value any) string {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]any{"v": value}); err != nil {
+		// Only plain values are passed in, which always encode
+		panic(err)
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(buf.String(), "v = "), "\n")
+}
+
+// tomlInlineValue formats a raw value, writing tables inline so the value fits on one line
+func tomlInlineValue(value any) string {
+	switch v := value.(type) {
+	case map[string]any:
+		settings := make([]string, 0, len(v))
+		for _, key := range sortedKeys(v) {
+			settings = append(settings, tomlKey(key)+" = "+tomlInlineValue(v[key]))
+		}
+		return "{ " + strings.Join(settings, ", ") + " }"
+	case []map[string]any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = tomlInlineValue(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = tomlInlineValue(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return tomlValue(v)
+	}
+}
+
+// renderManifestV2 writes the application as a version 2 "spin.toml" file.
+// Each trigger is followed by the component it runs, which is how Spin's templates lay out a manifest.
+// Only the settings a version 1 manifest can have are written, as the application always comes from convertV1.
+func renderManifestV2(tomlData *SpinTOML) string {
+	var b strings.Builder
+	writeField := func(key string, value any) {
+		switch v := value.(type) {
+		case string:
+			if v == "" {
+				return
+			}
+		case []string:
+			if len(v) == 0 {
+				return
+			}
+		}
+		fmt.Fprintf(&b, "%s = %s\n", tomlKey(key), tomlValue(value))
+	}
+
+	b.WriteString("spin_manifest_version = 2\n\n[application]\n")
+	writeField("name", tomlData.Application.Name)
+	writeField("version", tomlData.Application.Version)
+	writeField("authors", tomlData.Application.Authors)
+	writeField("description", tomlData.Application.Description)
+
+	if tomlData.Application.Trigger.HTTP.Base != "" {
+		b.WriteString("\n[application.trigger.http]\n")
+		writeField("base", tomlData.Application.Trigger.HTTP.Base)
+	}
+	if tomlData.Application.Trigger.Redis.Address != "" {
+		b.WriteString("\n[application.trigger.redis]\n")
+		writeField("address", tomlData.Application.Trigger.Redis.Address)
+	}
+
+	if len(tomlData.Variables) > 0 {
+		b.WriteString("\n[variables]\n")
+		for _, varKey := range sortedKeys(tomlData.Variables) {
+			varData := tomlData.Variables[varKey]
+			var settings []string
+			if varData.Default != "" {
+				settings = append(settings, "default = "+tomlValue(varData.Default))
+			}
+			if varData.Required {
+				settings = append(settings, "required = true")
+			}
+			if varData.Secret {
+				settings = append(settings, "secret = true")
+			}
+			fmt.Fprintf(&b, "%s = { %s }\n", tomlKey(varKey), strings.Join(settings, ", "))
+		}
+	}
+
+	written := make(map[string]bool)
+	writeComponent := func(name string) {
+		componentData, ok := tomlData.Component[name]
+		if !ok || written[name] {
+			return
+		}
+		written[name] = true
+
+		fmt.Fprintf(&b, "\n[component.%s]\n", tomlKey(name))
+		writeField("description", componentData.Description)
+		if componentData.Source.Struct != nil {
+			source := "url = " + tomlValue(componentData.Source.Struct.URL)
+			if componentData.Source.Struct.Digest != "" {
+				source += ", digest = " + tomlValue(componentData.Source.Struct.Digest)
+			}
+			fmt.Fprintf(&b, "source = { %s }\n", source)
+		} else {
+			writeField("source", componentData.Source.String)
+		}
+		writeField("allowed_outbound_hosts", componentData.AllowedOutboundHosts)
+		writeField("key_value_stores", componentData.KeyValueStores)
+		writeField("sqlite_databases", componentData.SQLiteDatabases)
+		writeField("ai_models", componentData.AIModels)
+
+		if len(componentData.Variables) > 0 {
+			fmt.Fprintf(&b, "[component.%s.variables]\n", tomlKey(name))
+			for _, varKey := range sortedKeys(componentData.Variables) {
+				writeField(varKey, componentData.Variables[varKey])
+			}
+		}
+
+		if len(componentData.Environment) > 0 {
+			fmt.Fprintf(&b, "[component.%s.environment]\n", tomlKey(name))
+			for _, envKey := range sortedKeys(componentData.Environment) {
+				writeField(envKey, componentData.Environment[envKey])
+			}
+		}
+	}
+
+	for _, httpTrigger := range tomlData.Trigger.HTTP {
+		b.WriteString("\n[[trigger.http]]\n")
+		if httpTrigger.Route.Struct != nil {
+			fmt.Fprintf(&b, "route = { private = %t }\n", httpTrigger.Route.Struct.Private)
+		} else {
+			writeField("route", httpTrigger.Route.String)
+		}
+		writeField("component", httpTrigger.Component)
+		if httpTrigger.Executor.Type != "" {
+			executor := "type = " + tomlValue(httpTrigger.Executor.Type)
+			if httpTrigger.Executor.Entrypoint != "" {
+				executor += ", entrypoint = " + tomlValue(httpTrigger.Executor.Entrypoint)
+			}
+			if httpTrigger.Executor.Argv != "" {
+				executor += ", argv = " + tomlValue(httpTrigger.Executor.Argv)
+			}
+			fmt.Fprintf(&b, "executor = { %s }\n", executor)
+		}
+		writeComponent(httpTrigger.Component)
+	}
+
+	for _, redisTrigger := range tomlData.Trigger.Redis {
+		b.WriteString("\n[[trigger.redis]]\n")
+		writeField("address", redisTrigger.Address)
+		writeField("channel", redisTrigger.Channel)
+		writeField("component", redisTrigger.Component)
+		writeComponent(redisTrigger.Component)
+	}
+
+	for _, otherTrigger := range tomlData.Trigger.Other {
+		fmt.Fprintf(&b, "\n[[trigger.%s]]\n", tomlKey(otherTrigger.TriggerType))
+		writeField("component", otherTrigger.Component)
+		for _, key := range sortedKeys(otherTrigger.Config) {
+			fmt.Fprintf(&b, "%s = %s\n", tomlKey(key), tomlInlineValue(otherTrigger.Config[key]))
+		}
+		writeComponent(otherTrigger.Component)
+	}
+
+	// Components without a trigger still belong in the manifest
+	for _, name := range sortedKeys(tomlData.Component) {
+		writeComponent(name)
+	}
+
+	return b.String()
+}
+
+// sortedKeys returns the keys of the map in alphabetical order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// The number of unchanged lines shown around every change in a diff
+const diffContext = 3
+
+// unifiedDiff compares two texts line by line and returns the changes in the unified diff format
+func unifiedDiff(oldName, newName, oldText, newText string) string {
+	oldLines := strings.Split(strings.TrimSuffix(oldText, "\n"), "\n")
+	newLines := strings.Split(strings.TrimSuffix(newText, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of oldLines[i:] and newLines[j:]
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type diffLine struct {
+		Op   byte
+		Text string
+		// The line numbers (starting at 0) in the old and new texts, before this line
+		Old, New int
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			lines = append(lines, diffLine{' ', oldLines[i], i, j})
+			i++
+			j++
+		case i < len(oldLines) && (j == len(newLines) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{'-', oldLines[i], i, j})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', newLines[j], i, j})
+			j++
+		}
+	}
+
+	var b strings.Builder
+	for start := 0; start < len(lines); {
+		if lines[start].Op == ' ' {
+			start++
+			continue
+		}
+
+		// Extending the hunk until there are more than twice the context lines without a change
+		hunkStart := max(start-diffContext, 0)
+		end := start
+		for k := start; k < len(lines) && k-end <= 2*diffContext; k++ {
+			if lines[k].Op != ' ' {
+				end = k
+			}
+		}
+		hunkEnd := min(end+diffContext+1, len(lines))
+
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+		}
+
+		var oldCount, newCount int
+		for _, line := range lines[hunkStart:hunkEnd] {
+			if line.Op != '+' {
+				oldCount++
+			}
+			if line.Op != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", lines[hunkStart].Old+1, oldCount, lines[hunkStart].New+1, newCount)
+		for _, line := range lines[hunkStart:hunkEnd] {
+			fmt.Fprintf(&b, "%c%s\n", line.Op, line.Text)
+		}
+
+		start = hunkEnd
+	}
+
+	return b.String()
+}
+
+// showMigrationNotes lists the constructs that need human attention after a migration
+func showMigrationNotes(notes []string) string {
+	if len(notes) == 0 {
+		return "\nNothing needs attention\n"
+	}
+
+	var b strings.Builder
+	b.WriteString("\nNeeds attention:\n")
+	for _, note := range notes {
+		fmt.Fprintf(&b, "  - %s\n", note)
+	}
+
+	return b.String()
+}
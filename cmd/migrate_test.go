@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestMigrateV1(t *testing.T) {
+	migrated, notes, err := migrateV1("../test_data/spin_v1.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The migrated manifest must describe the same application as the version 1 manifest
+	want, err := parseSpinToml("../test_data/spin_v1.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	migratedPath := filepath.Join(t.TempDir(), "spin.toml")
+	if err := os.WriteFile(migratedPath, []byte(migrated), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := parseSpinToml(migratedPath)
+	if err != nil {
+		t.Fatalf("the migrated manifest is invalid: %v\n%s", err, migrated)
+	}
+
+	if diff := cmp.Diff(*want, *got, cmpopts.IgnoreUnexported(Route{}, Source{})); diff != "" {
+		t.Errorf("migrateV1() mismatch (-want +got):\n%s", diff)
+	}
+
+	wantNotes := []string{
+		`component "number-one": "insecure:allow-all" became "*://*:*", which also allows database and Redis connections to any host`,
+		`component "number-one": the allowed host "example.com" has no scheme, so it was allowed over both HTTP and HTTPS; remove the one you don't need`,
+		`the setting "component.files" is not migrated; copy it to the version 2 component by hand`,
+		`the setting "component.build" is not migrated; copy it to the version 2 component by hand`,
+		`version 1 allowed Redis, PostgreSQL and MySQL connections to any host; add the hosts each component connects to its allowed_outbound_hosts`,
+	}
+	if diff := cmp.Diff(wantNotes, notes); diff != "" {
+		t.Errorf("migrateV1() notes mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMigrateV1RejectsV2(t *testing.T) {
+	if _, _, err := migrateV1("../test_data/spin.toml"); err == nil {
+		t.Errorf("expected an error for a version 2 manifest")
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	oldText := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	newText := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+
+	want := `--- old
++++ new
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -8,3 +8,4 @@
+ h
+ i
+ j
++k
+`
+	if diff := cmp.Diff(want, unifiedDiff("old", "new", oldText, newText)); diff != "" {
+		t.Errorf("unifiedDiff() mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := unifiedDiff("old", "new", oldText, oldText); got != "" {
+		t.Errorf("expected no diff for identical texts, got:\n%s", got)
+	}
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var mockServeCmd = &cobra.Command{
+	Use:   "mock-serve",
+	Short: "Serve a mock of a Spin application's HTTP routes, without building any component",
+	Long: `The "mock-serve" command starts a local HTTP server that routes requests like the application would,
+with the base path, exact routes taking precedence over wildcards and private routes unreachable.
+Each request gets a JSON response naming the component, the matched route, the path info and the resolved variables
+of the component, with secrets masked.
+
+With --fixtures, the response of a component is the content of "<fixtures>/<component>.json" instead, if that file exists.
+The file is read on every request, so it can be edited while the server runs.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, err := cmd.Flags().GetString("listen")
+		if err != nil {
+			return err
+		}
+		fixturesDir, err := cmd.Flags().GetString("fixtures")
+		if err != nil {
+			return err
+		}
+
+		tomlData, envVars, err := loadApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		values := maskSecrets(tomlData, resolveVariables(tomlData, envVars))
+		router := newMockRouter(tomlData, values, fixturesDir)
+
+		fmt.Print(showMockRoutes(router))
+		fmt.Printf("\nServing on http://%s\n", listen)
+		return http.ListenAndServe(listen, router)
+	},
+}
+
+func init() {
+	mockServeCmd.Flags().String("listen", "127.0.0.1:3000", "The address to serve on")
+	mockServeCmd.Flags().String("fixtures", "", "A directory of \"<component>.json\" files overriding the responses of the components")
+}
+
+// MockRoute is a public route served by the mock router
+type MockRoute struct {
+	Component string
+	// The route as in the manifest, with the base path
+	Route string
+	// The segments of the route before any wildcard, where ":name" segments match any value
+	segments []string
+	wildcard bool
+}
+
+// match returns the path info and the route parameters if the path matches the route.
+// Exact routes only match their own path, while wildcards also match a trailing slash and anything after it.
+func (r MockRoute) match(path string) (string, map[string]string, bool) {
+	// Splitting without trimming, so the empty segments of "/api/" or "//api" don't match a literal or a parameter
+	var pathSegments []string
+	if path != "/" {
+		pathSegments = strings.Split(strings.TrimPrefix(path, "/"), "/")
+	}
+	if len(pathSegments) < len(r.segments) || (!r.wildcard && len(pathSegments) != len(r.segments)) {
+		return "", nil, false
+	}
+
+	params := make(map[string]string)
+	for i, segment := range r.segments {
+		if strings.HasPrefix(segment, ":") && pathSegments[i] != "" {
+			params[segment[1:]] = pathSegments[i]
+		} else if segment != pathSegments[i] {
+			return "", nil, false
+		}
+	}
+
+	var pathInfo string
+	if rest := pathSegments[len(r.segments):]; r.wildcard && len(rest) > 0 {
+		pathInfo = "/" + strings.Join(rest, "/")
+	} else if r.wildcard && path == "/" {
+		pathInfo = "/"
+	}
+
+	return pathInfo, params, true
+}
+
+// params returns the number of ":name" segments in the route
+func (r MockRoute) params() int {
+	var count int
+	for _, segment := range r.segments {
+		if strings.HasPrefix(segment, ":") {
+			count++
+		}
+	}
+
+	return count
+}
+
+// MockRouter is an http.Handler routing requests to the components of an application like Spin does,
+// and answering with a description of the match rather than running the component
+type MockRouter struct {
+	// The public routes, in the order they are tried
+	Routes []MockRoute
+	// The resolved variables of every component, with secrets masked
+	variables   map[string]map[string]string
+	fixturesDir string
+}
+
+// newMockRouter builds the route table of the application. Private routes are left out, as they can't be reached over HTTP.
+// Exact routes are tried before wildcards, and wildcards with longer prefixes before shorter ones,
+// so the most specific route wins regardless of the order of the manifest.
+func newMockRouter(tomlData *SpinTOML, vars map[string]VariableValue, fixturesDir string) *MockRouter {
+	router := &MockRouter{variables: make(map[string]map[string]string), fixturesDir: fixturesDir}
+
+	for _, httpTrigger := range tomlData.Trigger.HTTP {
+		if httpTrigger.Route.String == "" {
+			continue
+		}
+
+		route := fullRoute(tomlData.Application.Trigger.HTTP.Base, httpTrigger.Route.String)
+		var segments []string
+		if trimmed := strings.Trim(route, "/"); trimmed != "" {
+			segments = strings.Split(trimmed, "/")
+		}
+		wildcard := len(segments) > 0 && segments[len(segments)-1] == "..."
+		if wildcard {
+			segments = segments[:len(segments)-1]
+		}
+
+		router.Routes = append(router.Routes, MockRoute{Component: httpTrigger.Component, Route: route, segments: segments, wildcard: wildcard})
+	}
+
+	sort.SliceStable(router.Routes, func(i, j int) bool {
+		a, b := router.Routes[i], router.Routes[j]
+		if a.wildcard != b.wildcard {
+			return !a.wildcard
+		}
+		if len(a.segments) != len(b.segments) {
+			return len(a.segments) > len(b.segments)
+		}
+		// Literal segments are more specific than parameters
+		return a.params() < b.params()
+	})
+
+	for name, componentData := range tomlData.Component {
+		resolved := make(map[string]string, len(componentData.Variables))
+		for key, template := range componentData.Variables {
+			resolved[key] = resolveField(template, vars)
+		}
+		router.variables[name] = resolved
+	}
+
+	return router
+}
+
+// MockResponse is the body of a response from the mock router
+type MockResponse struct {
+	Component string            `json:"component"`
+	Route     string            `json:"route"`
+	Path      string            `json:"path"`
+	PathInfo  string            `json:"path_info"`
+	Params    map[string]string `json:"params,omitempty"`
+	Variables map[string]string `json:"variables"`
+}
+
+func (m *MockRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	for _, route := range m.Routes {
+		pathInfo, params, ok := route.match(r.URL.Path)
+		if !ok {
+			continue
+		}
+
+		// The component is named like "probe http" expects it
+		w.Header().Set("spin-component", route.Component)
+
+		if m.fixturesDir != "" {
+			fixture, err := os.ReadFile(filepath.Join(m.fixturesDir, route.Component+".json"))
+			if err == nil {
+				w.Write(fixture)
+				return
+			}
+			if !errors.Is(err, os.ErrNotExist) {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+				return
+			}
+		}
+
+		if len(params) == 0 {
+			params = nil
+		}
+		variables := m.variables[route.Component]
+		if variables == nil {
+			// The component isn't defined in the manifest, but the response still has the same shape
+			variables = map[string]string{}
+		}
+		json.NewEncoder(w).Encode(MockResponse{
+			Component: route.Component,
+			Route:     route.Route,
+			Path:      r.URL.Path,
+			PathInfo:  pathInfo,
+			Params:    params,
+			Variables: variables,
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("no route matches %q", r.URL.Path)})
+}
+
+// showMockRoutes will display a table with the routes of the mock router, in the order they are tried
+func showMockRoutes(router *MockRouter) string {
+	if len(router.Routes) == 0 {
+		return "\nNo components have a public HTTP route\n"
+	}
+
+	routeTable := table.NewWriter()
+	routeTable.SetTitle("Mock Routes")
+	routeTable.AppendHeader(table.Row{"route", "component"})
+	for _, route := range router.Routes {
+		routeTable.AppendRow(table.Row{route.Route, route.Component})
+	}
+
+	return "\n" + routeTable.Render() + "\n"
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMockRouter(t *testing.T) {
+	tomlData := &SpinTOML{
+		Application: Application{Trigger: ApplicationTrigger{HTTP: ApplicationTriggerHTTP{Base: "/shop"}}},
+		Variables: map[string]Variable{
+			"api_key": {Secret: true},
+		},
+		Trigger: Trigger{
+			HTTP: []HTTPTrigger{
+				// The catch-all comes first, so the other routes only win by being more specific
+				{Route: Route{String: "/..."}, Component: "frontend"},
+				{Route: Route{String: "/api/..."}, Component: "api"},
+				{Route: Route{String: "/api/health"}, Component: "health"},
+				{Route: Route{String: "/users/:id"}, Component: "users"},
+				{Route: Route{String: "/users/me"}, Component: "profile"},
+				{Route: Route{Struct: &struct{ Private bool }{Private: true}}, Component: "internal"},
+			},
+		},
+		Component: map[string]Component{
+			"api": {Variables: map[string]string{"key": "{{ api_key }}", "region": "{{ region }}"}},
+		},
+	}
+	vars := maskSecrets(tomlData, map[string]VariableValue{"api_key": {Value: "hunter2"}, "region": {Value: "eu-west-1"}})
+
+	fixturesDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(fixturesDir, "profile.json"), []byte(`{"name": "fixture"}`), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	router := newMockRouter(tomlData, vars, fixturesDir)
+
+	var order []string
+	for _, route := range router.Routes {
+		order = append(order, route.Route)
+	}
+	wantOrder := []string{"/shop/api/health", "/shop/users/me", "/shop/users/:id", "/shop/api/...", "/shop/..."}
+	if diff := cmp.Diff(wantOrder, order); diff != "" {
+		t.Errorf("route order mismatch (-want +got):\n%s", diff)
+	}
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		want       MockResponse
+	}{
+		{
+			path:       "/shop/api/orders/42",
+			wantStatus: http.StatusOK,
+			want: MockResponse{
+				Component: "api", Route: "/shop/api/...", Path: "/shop/api/orders/42", PathInfo: "/orders/42",
+				Variables: map[string]string{"key": secretMask, "region": "eu-west-1"},
+			},
+		},
+		{
+			path:       "/shop/api/health",
+			wantStatus: http.StatusOK,
+			want:       MockResponse{Component: "health", Route: "/shop/api/health", Path: "/shop/api/health", Variables: map[string]string{}},
+		},
+		{
+			path:       "/shop/users/7",
+			wantStatus: http.StatusOK,
+			want: MockResponse{
+				Component: "users", Route: "/shop/users/:id", Path: "/shop/users/7",
+				Params: map[string]string{"id": "7"}, Variables: map[string]string{},
+			},
+		},
+		{
+			path:       "/shop/",
+			wantStatus: http.StatusOK,
+			want:       MockResponse{Component: "frontend", Route: "/shop/...", Path: "/shop/", PathInfo: "/", Variables: map[string]string{}},
+		},
+		{
+			path:       "/shop/api",
+			wantStatus: http.StatusOK,
+			want:       MockResponse{Component: "api", Route: "/shop/api/...", Path: "/shop/api", Variables: map[string]string{"key": secretMask, "region": "eu-west-1"}},
+		},
+		{
+			// Exact routes don't match a trailing slash, so the wildcard does
+			path:       "/shop/api/health/",
+			wantStatus: http.StatusOK,
+			want: MockResponse{
+				Component: "api", Route: "/shop/api/...", Path: "/shop/api/health/", PathInfo: "/health/",
+				Variables: map[string]string{"key": secretMask, "region": "eu-west-1"},
+			},
+		},
+		{
+			path:       "/shop/users/7/",
+			wantStatus: http.StatusOK,
+			want:       MockResponse{Component: "frontend", Route: "/shop/...", Path: "/shop/users/7/", PathInfo: "/users/7/", Variables: map[string]string{}},
+		},
+		{
+			path:       "/elsewhere",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			response, err := http.Get(server.URL + tt.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer response.Body.Close()
+
+			if response.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", response.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			if got := response.Header.Get("spin-component"); got != tt.want.Component {
+				t.Errorf("got spin-component header %q, want %q", got, tt.want.Component)
+			}
+
+			var got MockResponse
+			if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("response mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("fixture", func(t *testing.T) {
+		response, err := http.Get(server.URL + "/shop/users/me")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer response.Body.Close()
+
+		var got map[string]string
+		if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got["name"] != "fixture" {
+			t.Errorf("expected the fixture of the profile component, got %v", got)
+		}
+	})
+}
+
+func TestMockRouteMatch(t *testing.T) {
+	exact := MockRoute{Route: "/api", segments: []string{"api"}}
+	param := MockRoute{Route: "/users/:id", segments: []string{"users", ":id"}}
+	wildcard := MockRoute{Route: "/api/...", segments: []string{"api"}, wildcard: true}
+	root := MockRoute{Route: "/", segments: nil}
+
+	tests := []struct {
+		route        MockRoute
+		path         string
+		wantOK       bool
+		wantPathInfo string
+	}{
+		{route: exact, path: "/api", wantOK: true},
+		{route: exact, path: "/api/"},
+		{route: exact, path: "//api"},
+		{route: param, path: "/users/7", wantOK: true},
+		{route: param, path: "/users/"},
+		{route: param, path: "/users//"},
+		{route: wildcard, path: "/api", wantOK: true},
+		{route: wildcard, path: "/api/", wantOK: true, wantPathInfo: "/"},
+		{route: wildcard, path: "/api/v1/", wantOK: true, wantPathInfo: "/v1/"},
+		{route: wildcard, path: "//api"},
+		{route: root, path: "/", wantOK: true},
+		{route: root, path: "//"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.route.Route+" "+tt.path, func(t *testing.T) {
+			pathInfo, _, ok := tt.route.match(tt.path)
+			if ok != tt.wantOK || pathInfo != tt.wantPathInfo {
+				t.Errorf("match(%q) = %q, %t, want %q, %t", tt.path, pathInfo, ok, tt.wantPathInfo, tt.wantOK)
+			}
+		})
+	}
+}
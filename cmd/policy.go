@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Evaluate organisation policies against a Spin application",
+}
+
+var policyCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check a spin.toml file against the rules in a policy file",
+	Long: `The "policy check" command reads a spin.toml file and a policy file, then evaluates every rule
+in the policy against every component it applies to. The command exits with a non-zero status if any rule is violated.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policyPath, err := cmd.Flags().GetString("policy")
+		if err != nil {
+			return err
+		}
+
+		tomlData, _, err := loadApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		policy, err := parsePolicy(policyPath)
+		if err != nil {
+			return err
+		}
+
+		violations := checkPolicy(policy, tomlData)
+		fmt.Print(showPolicyViolations(violations))
+
+		if len(violations) > 0 {
+			return findingsError(cmd, "%d policy violation(s) found", len(violations))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	policyCheckCmd.Flags().StringP("policy", "p", "", "Specifies the path to the policy file to evaluate")
+	policyCheckCmd.MarkFlagRequired("policy")
+	policyCmd.AddCommand(policyCheckCmd)
+}
+
+// Policy is a set of rules evaluated against every component of a Spin application
+type Policy struct {
+	Rules []PolicyRule `toml:"rule"`
+}
+
+type PolicyRule struct {
+	Name        string `toml:"name"`
+	Description string `toml:"description"`
+
+	// Selectors limiting which components the rule applies to. A rule with no selectors applies to every component.
+	// Components are matched using glob patterns (e.g. "api-*").
+	Components  []string `toml:"components"`
+	Tags        []string `toml:"tags"`
+	ExcludeTags []string `toml:"exclude_tags"`
+
+	// The checks performed against each selected component
+	DenyOutboundHosts   []string `toml:"deny_outbound_hosts"`
+	DenyOutboundSchemes []string `toml:"deny_outbound_schemes"`
+	// This needs to be a pointer, so a rule that doesn't mention public routes is distinguishable from one that forbids them
+	AllowPublicRoutes   *bool `toml:"allow_public_routes"`
+	RequireSourceDigest bool  `toml:"require_source_digest"`
+}
+
+// PolicyViolation is a single rule that a component does not satisfy
+type PolicyViolation struct {
+	Rule      string
+	Component string
+	Message   string
+}
+
+func parsePolicy(filePath string) (*Policy, error) {
+	var policy Policy
+	md, err := toml.DecodeFile(filePath, &policy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rejecting unknown keys, so a typo in a rule doesn't silently disable a check
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("unknown key %q in policy file %q", undecoded[0].String(), filePath)
+	}
+
+	for i, rule := range policy.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule #%d in policy file %q has no name", i+1, filePath)
+		}
+
+		// A malformed pattern would never match, so the rule would silently never fire
+		for _, pattern := range rule.Components {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("rule %q in policy file %q has an invalid component pattern %q: %w", rule.Name, filePath, pattern, err)
+			}
+		}
+	}
+
+	return &policy, nil
+}
+
+// checkPolicy evaluates every rule against every component it applies to
+func checkPolicy(policy *Policy, tomlData *SpinTOML) []PolicyViolation {
+	var names []string
+	for name := range tomlData.Component {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var violations []PolicyViolation
+	for _, rule := range policy.Rules {
+		for _, name := range names {
+			componentData := tomlData.Component[name]
+			if !rule.appliesTo(name, componentData) {
+				continue
+			}
+
+			for _, message := range rule.check(tomlData, name, componentData) {
+				violations = append(violations, PolicyViolation{Rule: rule.Name, Component: name, Message: message})
+			}
+		}
+	}
+
+	return violations
+}
+
+// appliesTo reports whether a component is selected by the rule's selectors
+func (r PolicyRule) appliesTo(name string, componentData Component) bool {
+	if len(r.Components) > 0 {
+		matched := slices.ContainsFunc(r.Components, func(pattern string) bool {
+			// The patterns are validated by parsePolicy, so there is no error to handle
+			ok, _ := path.Match(pattern, name)
+			return ok
+		})
+		if !matched {
+			return false
+		}
+	}
+
+	tags := componentData.Tool.Blueprint.Tags
+	if len(r.Tags) > 0 && !slices.ContainsFunc(r.Tags, func(tag string) bool { return slices.Contains(tags, tag) }) {
+		return false
+	}
+	if slices.ContainsFunc(r.ExcludeTags, func(tag string) bool { return slices.Contains(tags, tag) }) {
+		return false
+	}
+
+	return true
+}
+
+// check returns a message for every way the component violates the rule
+func (r PolicyRule) check(tomlData *SpinTOML, name string, componentData Component) []string {
+	var messages []string
+
+	for _, host := range componentData.AllowedOutboundHosts {
+		if slices.Contains(r.DenyOutboundHosts, host) {
+			messages = append(messages, fmt.Sprintf("outbound host %q is not allowed", host))
+		}
+
+		scheme, _, _ := strings.Cut(host, "://")
+		if slices.Contains(r.DenyOutboundSchemes, scheme) {
+			messages = append(messages, fmt.Sprintf("outbound host %q uses the forbidden scheme %q", host, scheme))
+		}
+	}
+
+	if r.AllowPublicRoutes != nil && !*r.AllowPublicRoutes {
+		for _, httpTrigger := range tomlData.Trigger.HTTP {
+			if httpTrigger.Component == name && httpTrigger.Route.String != "" {
+				messages = append(messages, fmt.Sprintf("public route %q is not allowed", fullRoute(tomlData.Application.Trigger.HTTP.Base, httpTrigger.Route.String)))
+			}
+		}
+	}
+
+	if r.RequireSourceDigest && componentData.Source.Struct != nil && componentData.Source.Struct.Digest == "" {
+		messages = append(messages, fmt.Sprintf("source %q has no digest", componentData.Source.Struct.URL))
+	}
+
+	return messages
+}
+
+// showPolicyViolations will display a table with every policy violation
+func showPolicyViolations(violations []PolicyViolation) string {
+	if len(violations) == 0 {
+		return "\nNo policy violations found\n"
+	}
+
+	violationTable := table.NewWriter()
+	violationTable.SetTitle("Policy Violations")
+	violationTable.AppendHeader(table.Row{"rule", "component", "violation"})
+	for _, violation := range violations {
+		violationTable.AppendRow(table.Row{violation.Rule, violation.Component, violation.Message})
+	}
+
+	return "\n" + violationTable.Render() + "\n"
+}
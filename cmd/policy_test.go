@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheckPolicy(t *testing.T) {
+	policy, err := parsePolicy("../test_data/policy.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tomlData, err := parseSpinToml("../test_data/spin.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Adding components that violate the remaining rules
+	tomlData.Component["open"] = Component{
+		AllowedOutboundHosts: []string{"*://*:*"},
+		Source: Source{Struct: &struct {
+			URL    string `toml:"url"`
+			Digest string `toml:"digest"`
+		}{URL: "https://ghcr.io/fermyon/open"}},
+	}
+	tomlData.Component["edge"] = Component{Tool: ComponentTool{Blueprint: BlueprintComponentTool{Tags: []string{"edge"}}}}
+	tomlData.Trigger.HTTP = append(tomlData.Trigger.HTTP, HTTPTrigger{Route: Route{String: "/edge"}, Component: "edge"})
+
+	want := []PolicyViolation{
+		{Rule: "no-open-outbound", Component: "open", Message: `outbound host "*://*:*" is not allowed`},
+		{Rule: "edge-only-public-routes", Component: "number-one", Message: `public route "/blueprint/route-one/..." is not allowed`},
+		{Rule: "oci-digest", Component: "open", Message: `source "https://ghcr.io/fermyon/open" has no digest`},
+		{Rule: "no-plaintext-databases", Component: "number-one", Message: `outbound host "postgres://localhost:5432" uses the forbidden scheme "postgres"`},
+	}
+
+	if diff := cmp.Diff(want, checkPolicy(policy, tomlData)); diff != "" {
+		t.Errorf("checkPolicy() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParsePolicyUnknownKey(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.toml")
+	if err := os.WriteFile(policyPath, []byte("[[rule]]\nname = \"typo\"\ndeny_outbound_host = [\"*://*:*\"]\n"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := parsePolicy(policyPath); err == nil {
+		t.Errorf("expected an error for an unknown policy key")
+	}
+}
+
+func TestParsePolicyInvalidPattern(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.toml")
+	if err := os.WriteFile(policyPath, []byte("[[rule]]\nname = \"api-only\"\ncomponents = [\"api-[\"]\nrequire_source_digest = true\n"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := parsePolicy(policyPath)
+	if err == nil || !strings.Contains(err.Error(), `rule "api-only"`) {
+		t.Errorf("expected an error naming the rule, got %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var probeCmd = &cobra.Command{
+	Use:   "probe",
+	Short: "Smoke test the triggers of a Spin application against running services",
+}
+
+var probeRedisCmd = &cobra.Command{
+	Use:   "redis",
+	Short: "Publish a test message to the channel of every Redis trigger",
+	Long: `The "probe redis" command connects to the resolved address of every Redis trigger, which can be a local Redis
+or any stand-in speaking the Redis protocol, and publishes a test payload to the channel of each trigger.
+Connection and publishing failures are reported per address and channel.
+
+With --echo-channel, the command also subscribes to a reply channel before publishing, and waits for the component
+to echo the message back to confirm it was delivered. "{channel}" in the reply channel is replaced with the channel name.
+The command exits with a non-zero status if any probe fails.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		payload, err := cmd.Flags().GetString("payload")
+		if err != nil {
+			return err
+		}
+		echoChannel, err := cmd.Flags().GetString("echo-channel")
+		if err != nil {
+			return err
+		}
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			return err
+		}
+
+		tomlData, envVars, err := loadApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		// The real values are needed to connect, e.g. for a password in the address, so secrets are not masked here.
+		// The passwords are redacted from the addresses in the results instead.
+		values := resolveVariables(tomlData, envVars)
+		results := probeRedisChannels(buildRedisChannels(tomlData, values), payload, echoChannel, timeout)
+		fmt.Print(showRedisProbeResults(results))
+
+		var failures int
+		for _, result := range results {
+			if result.Problem != "" {
+				failures++
+			}
+		}
+		if failures > 0 {
+			return findingsError(cmd, "%d probe(s) failed", failures)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	probeRedisCmd.Flags().String("payload", "blueprint probe", "The message published to every channel")
+	probeRedisCmd.Flags().String("echo-channel", "", "A channel to wait on for the component to echo the message, e.g. \"{channel}.reply\"")
+	probeRedisCmd.Flags().Duration("timeout", 5*time.Second, "How long to wait for each connection, reply and echo")
+	probeCmd.AddCommand(probeRedisCmd)
+}
+
+// RedisProbeResult is the outcome of publishing a test message to a Redis channel
+type RedisProbeResult struct {
+	// The address of the Redis server, with any password redacted
+	Address     string
+	Channel     string
+	Subscribers []string
+	// The number of clients that received the message, according to the server
+	Receivers int64
+	// The message echoed back by the component, if an echo channel was given
+	Echo string
+	// A description of why the probe failed, or blank if it passed
+	Problem string
+}
+
+// redactRedisAddress replaces the password in a Redis address, so it can be displayed
+func redactRedisAddress(address string) string {
+	parsed, err := url.Parse(address)
+	if err != nil {
+		// The address can't be parsed, so any credentials are cut out by hand
+		if scheme, rest, ok := strings.Cut(address, "://"); ok {
+			if at := strings.LastIndex(rest, "@"); at >= 0 {
+				return scheme + "://xxxxx@" + rest[at+1:]
+			}
+		}
+		return address
+	}
+
+	return parsed.Redacted()
+}
+
+// probeRedisChannels publishes the payload to every channel, connecting once per address.
+// If the address can't be reached, every channel on it fails with the connection error.
+func probeRedisChannels(channels []RedisChannel, payload, echoChannel string, timeout time.Duration) []RedisProbeResult {
+	results := make([]RedisProbeResult, 0, len(channels))
+
+	conns := make(map[string]*RedisConn)
+	connErrs := make(map[string]error)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	for _, channel := range channels {
+		result := RedisProbeResult{Address: redactRedisAddress(channel.Address), Channel: channel.Channel, Subscribers: channel.Subscribers}
+		if channel.Problem != "" {
+			result.Problem = channel.Problem
+			results = append(results, result)
+			continue
+		}
+
+		if _, ok := conns[channel.Address]; !ok && connErrs[channel.Address] == nil {
+			conn, err := dialRedis(channel.Address, timeout)
+			if err == nil {
+				err = conn.Ping()
+				if err != nil {
+					conn.Close()
+				}
+			}
+			if err != nil {
+				connErrs[channel.Address] = err
+			} else {
+				conns[channel.Address] = conn
+			}
+		}
+		if err := connErrs[channel.Address]; err != nil {
+			result.Problem = "cannot connect: " + err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		result.Receivers, result.Echo, result.Problem = probeRedisChannel(conns[channel.Address], channel, payload, echoChannel, timeout)
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// probeRedisChannel publishes the payload to a single channel, waiting for the echo if there is an echo channel.
+// It returns the number of receivers, the echoed message and a description of the problem, if any.
+func probeRedisChannel(conn *RedisConn, channel RedisChannel, payload, echoChannel string, timeout time.Duration) (int64, string, string) {
+	if echoChannel == "" {
+		receivers, err := conn.Publish(channel.Channel, payload)
+		if err != nil {
+			return 0, "", "cannot publish: " + err.Error()
+		}
+		return receivers, "", ""
+	}
+
+	replyChannel := strings.ReplaceAll(echoChannel, "{channel}", channel.Channel)
+	if replyChannel == channel.Channel {
+		// The probe would receive its own message, which proves nothing
+		return 0, "", fmt.Sprintf("the echo channel %q is the channel itself", replyChannel)
+	}
+
+	// A subscribed connection can't publish, so the echo is received on a connection of its own
+	subscriber, err := dialRedis(channel.Address, timeout)
+	if err != nil {
+		return 0, "", "cannot connect: " + err.Error()
+	}
+	defer subscriber.Close()
+
+	if err := subscriber.Subscribe(replyChannel); err != nil {
+		return 0, "", fmt.Sprintf("cannot subscribe to %q: %v", replyChannel, err)
+	}
+
+	receivers, err := conn.Publish(channel.Channel, payload)
+	if err != nil {
+		return 0, "", "cannot publish: " + err.Error()
+	}
+
+	echo, err := subscriber.ReceiveMessage()
+	if err != nil {
+		return receivers, "", fmt.Sprintf("no echo on %q within %s", replyChannel, timeout)
+	}
+
+	return receivers, echo, ""
+}
+
+// showRedisProbeResults will display a table with the outcome of the probe of every Redis channel
+func showRedisProbeResults(results []RedisProbeResult) string {
+	if len(results) == 0 {
+		return "\nNo components have a Redis trigger\n"
+	}
+
+	probeTable := table.NewWriter()
+	probeTable.SetTitle("Redis Probe")
+	probeTable.AppendHeader(table.Row{"address", "channel", "subscribers", "receivers", "result"})
+	for _, result := range results {
+		var receivers, status string
+		switch {
+		case result.Problem != "":
+			// The problems contain addresses and channel names, so they are not upper-cased like other errors
+			status = "ERR: " + result.Problem
+		case result.Echo != "":
+			receivers = fmt.Sprint(result.Receivers)
+			status = fmt.Sprintf("OK, echoed %q", result.Echo)
+		default:
+			receivers = fmt.Sprint(result.Receivers)
+			status = "OK"
+		}
+		probeTable.AppendRow(table.Row{result.Address, result.Channel, strings.Join(result.Subscribers, "\n"), receivers, status})
+	}
+
+	return "\n" + probeTable.Render() + "\n"
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var probeHTTPCmd = &cobra.Command{
+	Use:   "http",
+	Short: "Send a request to every public HTTP route of a running Spin application",
+	Long: `The "probe http" command sends a request to every route of the application that isn't private, on a running
+instance of the application such as "spin up" or a deployment. Wildcard routes ("/...") and parameters (":name")
+are filled in with a sample value.
+
+A route passes if its status is below 400. If the response names the component that served it in the header given by
+--component-header, the route only passes if that is the component the route belongs to.
+The command exits with a non-zero status if any route fails.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		baseURL, err := cmd.Flags().GetString("base-url")
+		if err != nil {
+			return err
+		}
+		method, err := cmd.Flags().GetString("method")
+		if err != nil {
+			return err
+		}
+		sample, err := cmd.Flags().GetString("sample")
+		if err != nil {
+			return err
+		}
+		componentHeader, err := cmd.Flags().GetString("component-header")
+		if err != nil {
+			return err
+		}
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			return err
+		}
+
+		tomlData, _, err := loadApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		client := &http.Client{Timeout: timeout}
+		results := probeHTTPRoutes(client, baseURL, method, componentHeader, httpProbeTargets(tomlData, sample))
+		fmt.Print(showHTTPProbeResults(results))
+
+		var failures int
+		for _, result := range results {
+			if result.Problem != "" {
+				failures++
+			}
+		}
+		if failures > 0 {
+			return findingsError(cmd, "%d route(s) failed", failures)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	probeHTTPCmd.Flags().String("base-url", "http://127.0.0.1:3000", "The URL the application is served at")
+	probeHTTPCmd.Flags().String("method", http.MethodGet, "The HTTP method of the requests")
+	probeHTTPCmd.Flags().String("sample", "blueprint-probe", "The value wildcards and route parameters are filled in with")
+	probeHTTPCmd.Flags().String("component-header", "spin-component", "The response header naming the component that served the request")
+	probeHTTPCmd.Flags().Duration("timeout", 10*time.Second, "How long to wait for each response")
+	probeCmd.AddCommand(probeHTTPCmd)
+}
+
+// fullRoute prepends the application base path to a route. The base is "/" unless the manifest sets one.
+func fullRoute(base, route string) string {
+	return strings.TrimSuffix(base, "/") + route
+}
+
+// HTTPProbeTarget is a route to probe, with the path a request to it is sent to
+type HTTPProbeTarget struct {
+	Component string
+	// The route as in the manifest, with the base path
+	Route string
+	Path  string
+}
+
+// httpProbeTargets returns a target for every route that isn't private, in the order of the manifest.
+// The wildcard at the end of a route and the ":name" parameters of a route are replaced with the sample value.
+func httpProbeTargets(tomlData *SpinTOML, sample string) []HTTPProbeTarget {
+	var targets []HTTPProbeTarget
+	for _, httpTrigger := range tomlData.Trigger.HTTP {
+		if httpTrigger.Route.String == "" {
+			continue
+		}
+
+		route := fullRoute(tomlData.Application.Trigger.HTTP.Base, httpTrigger.Route.String)
+		segments := strings.Split(route, "/")
+		for i, segment := range segments {
+			if segment == "..." || strings.HasPrefix(segment, ":") {
+				segments[i] = sample
+			}
+		}
+
+		targets = append(targets, HTTPProbeTarget{Component: httpTrigger.Component, Route: route, Path: strings.Join(segments, "/")})
+	}
+
+	return targets
+}
+
+// HTTPProbeResult is the outcome of a request to a route
+type HTTPProbeResult struct {
+	HTTPProbeTarget
+	Status  int
+	Latency time.Duration
+	// The component named by the component header of the response, or blank if there was no such header
+	ServedBy string
+	// A description of why the route failed, or blank if it passed
+	Problem string
+}
+
+// probeHTTPRoutes sends a request to every target, one at a time so the latencies aren't skewed by each other
+func probeHTTPRoutes(client *http.Client, baseURL, method, componentHeader string, targets []HTTPProbeTarget) []HTTPProbeResult {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	results := make([]HTTPProbeResult, 0, len(targets))
+	for _, target := range targets {
+		result := HTTPProbeResult{HTTPProbeTarget: target}
+
+		request, err := http.NewRequest(method, baseURL+target.Path, nil)
+		if err != nil {
+			result.Problem = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		start := time.Now()
+		response, err := client.Do(request)
+		if err != nil {
+			// The URL is already in the table, so only the underlying error is kept
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				err = urlErr.Err
+			}
+			result.Problem = err.Error()
+			results = append(results, result)
+			continue
+		}
+		// The body is read so the latency covers the whole response
+		io.Copy(io.Discard, response.Body)
+		response.Body.Close()
+		result.Latency = time.Since(start)
+
+		result.Status = response.StatusCode
+		if componentHeader != "" {
+			result.ServedBy = response.Header.Get(componentHeader)
+		}
+
+		switch {
+		case response.StatusCode >= 400:
+			result.Problem = "status " + response.Status
+		case result.ServedBy != "" && result.ServedBy != target.Component:
+			result.Problem = fmt.Sprintf("served by %q instead of %q", result.ServedBy, target.Component)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// showHTTPProbeResults will display a table with the outcome of the request to every route
+func showHTTPProbeResults(results []HTTPProbeResult) string {
+	if len(results) == 0 {
+		return "\nNo components have a public HTTP route\n"
+	}
+
+	probeTable := table.NewWriter()
+	probeTable.SetTitle("HTTP Probe")
+	probeTable.AppendHeader(table.Row{"route", "path", "component", "status", "latency", "served by", "result"})
+	for _, result := range results {
+		var status, latency string
+		if result.Status != 0 {
+			status = fmt.Sprint(result.Status)
+			latency = fmt.Sprintf("%.1fms", float64(result.Latency.Microseconds())/1000)
+		}
+
+		outcome := "PASS"
+		if result.Problem != "" {
+			outcome = "FAIL: " + result.Problem
+		}
+
+		probeTable.AppendRow(table.Row{result.Route, result.Path, result.Component, status, latency, result.ServedBy, outcome})
+	}
+
+	return "\n" + probeTable.Render() + "\n"
+}
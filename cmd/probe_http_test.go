@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHTTPProbeTargets(t *testing.T) {
+	tomlData := &SpinTOML{
+		Application: Application{Trigger: ApplicationTrigger{HTTP: ApplicationTriggerHTTP{Base: "/shop/"}}},
+		Trigger: Trigger{
+			HTTP: []HTTPTrigger{
+				{Route: Route{String: "/"}, Component: "frontend"},
+				{Route: Route{String: "/api/..."}, Component: "api"},
+				{Route: Route{String: "/users/:id/orders"}, Component: "orders"},
+				{Route: Route{Struct: &struct{ Private bool }{Private: true}}, Component: "internal"},
+			},
+		},
+	}
+
+	got := httpProbeTargets(tomlData, "sample")
+	want := []HTTPProbeTarget{
+		{Component: "frontend", Route: "/shop/", Path: "/shop/"},
+		{Component: "api", Route: "/shop/api/...", Path: "/shop/api/sample"},
+		{Component: "orders", Route: "/shop/users/:id/orders", Path: "/shop/users/sample/orders"},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("httpProbeTargets() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestProbeHTTPRoutes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/sample":
+			w.Header().Set("spin-component", "api")
+		case "/wrong":
+			w.Header().Set("spin-component", "frontend")
+		case "/missing":
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	targets := []HTTPProbeTarget{
+		{Component: "api", Route: "/api/...", Path: "/api/sample"},
+		{Component: "static", Route: "/static", Path: "/static"},
+		{Component: "admin", Route: "/wrong", Path: "/wrong"},
+		{Component: "docs", Route: "/missing", Path: "/missing"},
+	}
+
+	results := probeHTTPRoutes(server.Client(), server.URL+"/", http.MethodGet, "spin-component", targets)
+
+	// The latencies vary, so they are only checked for being measured
+	for i := range results {
+		if results[i].Latency <= 0 {
+			t.Errorf("expected a latency for %s", results[i].Path)
+		}
+		results[i].Latency = 0
+	}
+
+	want := []HTTPProbeResult{
+		{HTTPProbeTarget: targets[0], Status: 200, ServedBy: "api"},
+		{HTTPProbeTarget: targets[1], Status: 200},
+		{HTTPProbeTarget: targets[2], Status: 200, ServedBy: "frontend", Problem: `served by "frontend" instead of "admin"`},
+		{HTTPProbeTarget: targets[3], Status: 404, Problem: "status 404 Not Found"},
+	}
+
+	if diff := cmp.Diff(want, results); diff != "" {
+		t.Errorf("probeHTTPRoutes() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestProbeHTTPRoutesUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	client := &http.Client{Timeout: time.Second}
+	results := probeHTTPRoutes(client, server.URL, http.MethodGet, "spin-component", []HTTPProbeTarget{{Component: "api", Route: "/", Path: "/"}})
+
+	if results[0].Problem == "" || results[0].Status != 0 {
+		t.Errorf("expected a connection failure, got %+v", results[0])
+	}
+}
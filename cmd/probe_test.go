@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeRedis is a stand-in Redis server supporting PING, AUTH, PUBLISH and SUBSCRIBE.
+// Messages published to the channels in echo are published back to "<channel>.reply", like a component echoing them.
+type fakeRedis struct {
+	listener    net.Listener
+	password    string
+	echo        map[string]bool
+	mu          sync.Mutex
+	subscribers map[string][]net.Conn
+}
+
+func startFakeRedis(t *testing.T, password string, echo ...string) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeRedis{listener: listener, password: password, echo: make(map[string]bool), subscribers: make(map[string][]net.Conn)}
+	for _, channel := range echo {
+		server.echo[channel] = true
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (s *fakeRedis) address() string {
+	if s.password != "" {
+		return "redis://:" + s.password + "@" + s.listener.Addr().String()
+	}
+	return "redis://" + s.listener.Addr().String()
+}
+
+func (s *fakeRedis) publish(channel, message string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, subscriber := range s.subscribers[channel] {
+		fmt.Fprintf(subscriber, "*3\r\n$7\r\nmessage\r\n$%d\r\n%s\r\n$%d\r\n%s\r\n", len(channel), channel, len(message), message)
+	}
+	return int64(len(s.subscribers[channel]))
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+
+	authenticated := s.password == ""
+	reader := bufio.NewReader(conn)
+	for {
+		request, err := readRESP(reader)
+		if err != nil {
+			return
+		}
+		args, _ := request.([]any)
+		if len(args) == 0 {
+			return
+		}
+
+		command := strings.ToUpper(args[0].(string))
+		switch {
+		case command == "AUTH":
+			if args[len(args)-1] != s.password {
+				fmt.Fprint(conn, "-WRONGPASS invalid password\r\n")
+				continue
+			}
+			authenticated = true
+			fmt.Fprint(conn, "+OK\r\n")
+		case !authenticated:
+			fmt.Fprint(conn, "-NOAUTH Authentication required.\r\n")
+		case command == "PING":
+			fmt.Fprint(conn, "+PONG\r\n")
+		case command == "PUBLISH":
+			channel, message := args[1].(string), args[2].(string)
+			fmt.Fprintf(conn, ":%d\r\n", s.publish(channel, message))
+			if s.echo[channel] {
+				s.publish(channel+".reply", "echo: "+message)
+			}
+		case command == "SUBSCRIBE":
+			channel := args[1].(string)
+			s.mu.Lock()
+			s.subscribers[channel] = append(s.subscribers[channel], conn)
+			fmt.Fprintf(conn, "*3\r\n$9\r\nsubscribe\r\n$%d\r\n%s\r\n:1\r\n", len(channel), channel)
+			s.mu.Unlock()
+		default:
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", command)
+		}
+	}
+}
+
+func TestProbeRedisChannels(t *testing.T) {
+	server := startFakeRedis(t, "hunter2", "orders")
+
+	// Nothing listens on the port of a closed listener, so connecting to it fails
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	closedAddress := "redis://" + closed.Addr().String()
+	closed.Close()
+
+	channels := []RedisChannel{
+		{Address: server.address(), Channel: "orders", Subscribers: []string{"fulfilment"}},
+		{Address: server.address(), Channel: "audit", Subscribers: []string{"auditor"}},
+		{Address: closedAddress, Channel: "lost", Subscribers: []string{"orphan"}},
+		{Address: "redis://{{ missing }}", Channel: "broken", Subscribers: []string{"broken"}, Problem: `offset 8: variable "missing" is not declared`},
+	}
+
+	t.Run("publish", func(t *testing.T) {
+		results := probeRedisChannels(channels, "ping", "", time.Second)
+		if len(results) != 4 {
+			t.Fatalf("got %d results, want 4", len(results))
+		}
+
+		redacted := "redis://:xxxxx@" + server.listener.Addr().String()
+		want := []RedisProbeResult{
+			{Address: redacted, Channel: "orders", Subscribers: []string{"fulfilment"}},
+			{Address: redacted, Channel: "audit", Subscribers: []string{"auditor"}},
+		}
+		if diff := cmp.Diff(want, results[:2]); diff != "" {
+			t.Errorf("probeRedisChannels() mismatch (-want +got):\n%s", diff)
+		}
+
+		if !strings.HasPrefix(results[2].Problem, "cannot connect: ") {
+			t.Errorf("expected a connection failure for %s, got %q", closedAddress, results[2].Problem)
+		}
+		if results[3].Problem != channels[3].Problem {
+			t.Errorf("expected the address problem to be kept, got %q", results[3].Problem)
+		}
+	})
+
+	t.Run("echo", func(t *testing.T) {
+		results := probeRedisChannels(channels[:2], "ping", "{channel}.reply", 200*time.Millisecond)
+
+		if results[0].Echo != "echo: ping" || results[0].Problem != "" {
+			t.Errorf("expected the echo on orders.reply, got %+v", results[0])
+		}
+		if results[1].Problem != `no echo on "audit.reply" within 200ms` {
+			t.Errorf("expected no echo on audit.reply, got %+v", results[1])
+		}
+	})
+
+	t.Run("invalid_address", func(t *testing.T) {
+		// The space makes the address unparsable, and the password must not leak through the error
+		invalid := []RedisChannel{{Address: "redis://user:pass word@" + server.listener.Addr().String(), Channel: "orders"}}
+		results := probeRedisChannels(invalid, "ping", "", time.Second)
+
+		want := RedisProbeResult{Address: "redis://xxxxx@" + server.listener.Addr().String(), Channel: "orders", Problem: "cannot connect: invalid address"}
+		if diff := cmp.Diff(want, results[0]); diff != "" {
+			t.Errorf("probeRedisChannels() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("wrong_password", func(t *testing.T) {
+		wrong := []RedisChannel{{Address: "redis://:wrong@" + server.listener.Addr().String(), Channel: "orders"}}
+		results := probeRedisChannels(wrong, "ping", "", time.Second)
+
+		if want := "cannot connect: authentication failed: WRONGPASS invalid password"; results[0].Problem != want {
+			t.Errorf("got problem %q, want %q", results[0].Problem, want)
+		}
+	})
+}
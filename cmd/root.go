@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -19,11 +20,36 @@ func Execute() {
 	}
 }
 
+// findingsError returns the error of a command that found count problems, e.g. "%d problem(s) found".
+// The problems have already been printed, so the usage text is silenced, as it would only add noise.
+func findingsError(cmd *cobra.Command, format string, count int) error {
+	cmd.SilenceUsage = true
+	return fmt.Errorf(format, count)
+}
+
 var All bool
 
+// RevealSecrets disables the masking of secret variable values
+var RevealSecrets bool
+
 func init() {
-	showCmd.PersistentFlags().StringP("file", "f", "", "Specifies the path to the spin.toml file you wish to visualize")
-	showCmd.PersistentFlags().StringP("env", "e", "", "Specifies the path to the \".env\" file containing your Spin variables")
-	showCmd.PersistentFlags().BoolVarP(&All, "all", "a", false, "Output information about all component. Only applies if no component name is specified.")
+	// The manifest and env flags are shared by every command that reads a Spin application
+	rootCmd.PersistentFlags().StringP("file", "f", "", "Specifies the path to the spin.toml file you wish to visualize")
+	rootCmd.PersistentFlags().StringArrayP("env", "e", nil, "Specifies the path to a \".env\" file containing your Spin variables. Can be repeated, with later files taking precedence")
+	rootCmd.PersistentFlags().String("runtime-config-file", "", "Specifies the path to a Spin runtime config file, whose config providers (e.g. vault) supply variable values")
+	rootCmd.PersistentFlags().BoolVar(&RevealSecrets, "reveal-secrets", false, "Show the values of secret variables instead of masking them")
+	showCmd.Flags().BoolVarP(&All, "all", "a", false, "Output information about all component. Only applies if no component name is specified.")
 	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(chainsCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(policyCmd)
+	rootCmd.AddCommand(varsCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(secretsCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(depsCmd)
+	rootCmd.AddCommand(cronCmd)
+	rootCmd.AddCommand(channelsCmd)
+	rootCmd.AddCommand(probeCmd)
+	rootCmd.AddCommand(mockServeCmd)
 }
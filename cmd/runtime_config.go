@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// RuntimeConfig is the subset of a Spin runtime config file that affects how variables are resolved
+type RuntimeConfig struct {
+	ConfigProviders []ConfigProvider `toml:"config_provider"`
+}
+
+// ConfigProvider is a "[[config_provider]]" entry, which provides values for variables
+type ConfigProvider struct {
+	Type string `toml:"type"`
+
+	// Settings for the "vault" provider
+	URL    string `toml:"url"`
+	Token  string `toml:"token"`
+	Mount  string `toml:"mount"`
+	Prefix string `toml:"prefix"`
+}
+
+func parseRuntimeConfig(filePath string) (*RuntimeConfig, error) {
+	var runtimeConfig RuntimeConfig
+	if _, err := toml.DecodeFile(filePath, &runtimeConfig); err != nil {
+		return nil, err
+	}
+
+	for i, provider := range runtimeConfig.ConfigProviders {
+		if provider.Type == "vault" && (provider.URL == "" || provider.Token == "" || provider.Mount == "") {
+			return nil, fmt.Errorf("config provider #%d in %q: the vault provider requires \"url\", \"token\" and \"mount\"", i+1, filePath)
+		}
+	}
+
+	return &runtimeConfig, nil
+}
+
+// resolveProviderVariables looks up the variables that weren't passed via env vars in the runtime config providers.
+// Like in Spin, the providers are tried in order and env vars always take precedence.
+// The values found are added to envVars, so every view treats them like any other passed-in value.
+func resolveProviderVariables(runtimeConfig *RuntimeConfig, tomlData *SpinTOML, envVars map[string]VariableValue) error {
+	var missing []string
+	for varKey := range tomlData.Variables {
+		if _, ok := envVars[varKey]; !ok {
+			missing = append(missing, varKey)
+		}
+	}
+	sort.Strings(missing)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	for _, provider := range runtimeConfig.ConfigProviders {
+		if provider.Type != "vault" {
+			// Other providers (e.g. Azure Key Vault) are not supported yet, so their variables remain unresolved
+			continue
+		}
+
+		for _, varKey := range missing {
+			if _, ok := envVars[varKey]; ok {
+				continue
+			}
+
+			value, secretPath, found, err := readVaultVariable(client, provider, varKey)
+			if err != nil {
+				return err
+			}
+
+			if found {
+				envVars[varKey] = VariableValue{Value: value, Source: VariableSource{Kind: SourceVault, File: secretPath}}
+			}
+		}
+	}
+
+	return nil
+}
+
+// readVaultVariable reads a variable from a Vault KV v2 secrets engine, like Spin's vault provider does.
+// The variable is stored as a secret at "<prefix>/<name>" with the value in its "value" key.
+// It returns the value, the path of the secret and whether the secret exists.
+func readVaultVariable(client *http.Client, provider ConfigProvider, varKey string) (string, string, bool, error) {
+	secretPath := varKey
+	if provider.Prefix != "" {
+		secretPath = path.Join(provider.Prefix, varKey)
+	}
+
+	endpoint, err := url.JoinPath(provider.URL, "v1", provider.Mount, "data", secretPath)
+	if err != nil {
+		return "", "", false, fmt.Errorf("invalid vault URL %q: %w", provider.URL, err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", "", false, err
+	}
+	req.Header.Set("X-Vault-Token", provider.Token)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", false, fmt.Errorf("failed to read variable %q from vault: %w", varKey, err)
+	}
+	defer resp.Body.Close()
+
+	displayPath := strings.TrimSuffix(provider.Mount, "/") + "/" + secretPath
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", displayPath, false, nil
+	default:
+		return "", displayPath, false, fmt.Errorf("failed to read variable %q from vault: %s", varKey, resp.Status)
+	}
+
+	var secret struct {
+		Data struct {
+			Data struct {
+				Value *string `json:"value"`
+			} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", displayPath, false, fmt.Errorf("failed to decode the vault secret for variable %q: %w", varKey, err)
+	}
+
+	if secret.Data.Data.Value == nil {
+		return "", displayPath, false, fmt.Errorf("the vault secret %q has no \"value\" key", displayPath)
+	}
+
+	return *secret.Data.Data.Value, displayPath, true, nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newFakeVault starts a local stand-in for the Vault KV v2 HTTP API, serving the secrets keyed by path
+func newFakeVault(t *testing.T, token string, secrets map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != token {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		value, ok := secrets[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[]}`)
+			return
+		}
+
+		fmt.Fprintf(w, `{"data":{"data":{"value":%q},"metadata":{"version":1}}}`, value)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestResolveProviderVariables(t *testing.T) {
+	vault := newFakeVault(t, "test-token", map[string]string{
+		"/v1/secret/data/blueprint/secret_var":           "from_vault",
+		"/v1/secret/data/blueprint/missing_required_var": "required_from_vault",
+		"/v1/secret/data/blueprint/test_var":             "ignored_because_env_wins",
+	})
+
+	runtimeConfigPath := filepath.Join(t.TempDir(), "runtime-config.toml")
+	runtimeConfig := fmt.Sprintf("[[config_provider]]\ntype = \"vault\"\nurl = %q\ntoken = \"test-token\"\nmount = \"secret\"\nprefix = \"blueprint\"\n", vault.URL)
+	if err := os.WriteFile(runtimeConfigPath, []byte(runtimeConfig), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parsedConfig, err := parseRuntimeConfig(runtimeConfigPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tomlData, err := parseSpinToml("../test_data/spin.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	envVars := map[string]VariableValue{
+		"test_var": {Value: "from_env", Source: VariableSource{Kind: SourceProcessEnv}},
+	}
+	if err := resolveProviderVariables(parsedConfig, tomlData, envVars); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]VariableValue{
+		"test_var":             {Value: "from_env", Source: VariableSource{Kind: SourceProcessEnv}},
+		"secret_var":           {Value: "from_vault", Source: VariableSource{Kind: SourceVault, File: "secret/blueprint/secret_var"}},
+		"missing_required_var": {Value: "required_from_vault", Source: VariableSource{Kind: SourceVault, File: "secret/blueprint/missing_required_var"}},
+	}
+	if diff := cmp.Diff(want, envVars); diff != "" {
+		t.Errorf("resolveProviderVariables() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestResolveProviderVariablesBadToken(t *testing.T) {
+	vault := newFakeVault(t, "test-token", nil)
+	runtimeConfig := &RuntimeConfig{ConfigProviders: []ConfigProvider{
+		{Type: "vault", URL: vault.URL, Token: "wrong-token", Mount: "secret"},
+	}}
+
+	tomlData := &SpinTOML{Variables: map[string]Variable{"api_key": {Secret: true}}}
+	if err := resolveProviderVariables(runtimeConfig, tomlData, map[string]VariableValue{}); err == nil {
+		t.Errorf("expected an error for a rejected token")
+	}
+}
@@ -1,13 +1,10 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
+	"maps"
 	"os"
-	"regexp"
 	"strings"
-	"sync"
-	"text/template"
 
 	"github.com/BurntSushi/toml"
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -22,35 +19,7 @@ You can optionally specify a component to display information for a specific com
 By default, the command looks for a "spin.toml" file in the current directory.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// The path to a "spin.toml" file
-		path, err := cmd.Flags().GetString("file")
-		if err != nil {
-			return err
-		}
-
-		// The path to a ".env" file (parseEnvVars will handle blank paths)
-		env, err := cmd.Flags().GetString("env")
-		if err != nil {
-			return err
-		}
-
-		if path == "" {
-			path = "spin.toml"
-		}
-
-		if _, err := os.Stat(path); err != nil {
-			if os.IsNotExist(err) {
-				return fmt.Errorf("the path %q does not exist", path)
-			}
-			return err
-		}
-
-		tomlData, err := parseSpinToml(path)
-		if err != nil {
-			return err
-		}
-
-		envVars, err := parseEnvVars(env)
+		tomlData, envVars, err := loadApp(cmd)
 		if err != nil {
 			return err
 		}
@@ -59,13 +28,17 @@ By default, the command looks for a "spin.toml" file in the current directory.`,
 			// This won't throw errors because we are not checking the validity of a "spin.toml" file
 			fmt.Print(showAllComponents(tomlData, envVars))
 
-		// Also print info about all components if --all flag is set
-		if All {
-			for name, _ := range tomlData.Component {
-				fmt.Print(showSpecificComponent(tomlData, envVars, name))
-			}
-		}
+			// Also print info about all components if --all flag is set
+			if All {
+				for name := range tomlData.Component {
+					terminalOutput, err := showSpecificComponent(tomlData, envVars, name)
+					if err != nil {
+						return err
+					}
 
+					fmt.Print(terminalOutput)
+				}
+			}
 		} else {
 			terminalOutput, err := showSpecificComponent(tomlData, envVars, args[0])
 			if err != nil {
@@ -79,8 +52,75 @@ By default, the command looks for a "spin.toml" file in the current directory.`,
 	},
 }
 
+// loadApp reads the "spin.toml" file and the Spin variables referenced by the
+// "--file" and "--env" flags. It is shared by every command that inspects an application.
+func loadApp(cmd *cobra.Command) (*SpinTOML, map[string]VariableValue, error) {
+	path, err := manifestPath(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The paths to ".env" files (parseEnvFiles will handle no paths)
+	envPaths, err := cmd.Flags().GetStringArray("env")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tomlData, err := parseSpinToml(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	envVars, err := parseEnvFiles(envPaths)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The path to a Spin runtime config file, whose config providers can supply variables
+	runtimeConfigPath, err := cmd.Flags().GetString("runtime-config-file")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if runtimeConfigPath != "" {
+		runtimeConfig, err := parseRuntimeConfig(runtimeConfigPath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if err := resolveProviderVariables(runtimeConfig, tomlData, envVars); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return tomlData, envVars, nil
+}
+
+// manifestPath returns the path to the "spin.toml" file given by the "--file" flag,
+// defaulting to the current directory, and checks that it exists
+func manifestPath(cmd *cobra.Command) (string, error) {
+	// The path to a "spin.toml" file
+	path, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return "", err
+	}
+
+	if path == "" {
+		path = "spin.toml"
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("the path %q does not exist", path)
+		}
+		return "", err
+	}
+
+	return path, nil
+}
+
 // showAllComponents will display a table with all the components listed in a "spin.toml" file
-func showAllComponents(tomlData *SpinTOML, envVars map[string]string) string {
+func showAllComponents(tomlData *SpinTOML, envVars map[string]VariableValue) string {
 	componentTable := table.NewWriter()
 	componentTable.SetTitle("Components")
 	componentTable.AppendHeader(table.Row{"name", "source"})
@@ -99,6 +139,13 @@ func showAllComponents(tomlData *SpinTOML, envVars map[string]string) string {
 		annotations = append(annotations, "* Authors: "+strings.Join(tomlData.Application.Authors, ", "))
 	}
 
+	// The application-level trigger settings apply to every component with a trigger of that type
+	values := maskSecrets(tomlData, resolveVariables(tomlData, envVars))
+	appTriggerSettings := applicationTriggerSettings(tomlData, values)
+	for _, triggerType := range sortedKeys(appTriggerSettings) {
+		annotations = append(annotations, "* Trigger Settings ("+triggerType+"): "+appTriggerSettings[triggerType])
+	}
+
 	for name, data := range tomlData.Component {
 		var source string
 
@@ -113,26 +160,28 @@ func showAllComponents(tomlData *SpinTOML, envVars map[string]string) string {
 
 	variableTable := table.NewWriter()
 	variableTable.SetTitle("Variables")
-	variableTable.AppendHeader(table.Row{"env_key", "env_value", "is_required", "is_secret", "is_default"})
+	variableTable.AppendHeader(table.Row{"env_key", "env_value", "is_required", "is_secret", "is_default", "source"})
 	for varKey, varData := range tomlData.Variables {
 		var matchExists bool
-		for envKey, envValue := range envVars {
+		for envKey, envVar := range envVars {
 			if varKey == envKey {
 				matchExists = true
 				// In the case where someone passes in a env var value that matches the default value,
 				// this will show false because this is using the env var value.
 				// ("is_default" == true) only applies to nothing being passed via env vars.
-				variableTable.AppendRow(table.Row{envKey, envValue, varData.Required, varData.Secret, "false"})
+				// The "source" column shows exactly where the value came from.
+				variableTable.AppendRow(table.Row{envKey, maskSecret(envVar.Value, varData.Secret), varData.Required, varData.Secret, "false", envVar.Source})
 			}
 		}
 
 		if !matchExists {
+			unresolved := VariableSource{Kind: SourceUnresolved}
 			if varData.Required {
-				variableTable.AppendRow(table.Row{varKey, "ERR: MISSING REQUIRED VALUE", true, "n/a", "n/a"})
+				variableTable.AppendRow(table.Row{varKey, "ERR: MISSING REQUIRED VALUE", true, "n/a", "n/a", unresolved})
 			} else if varData.Default == "" {
-				variableTable.AppendRow(table.Row{varKey, "ERR: ENV VAR NOT FOUND, DEFAULT NOT DEFINED", false, "n/a", "n/a"})
+				variableTable.AppendRow(table.Row{varKey, "ERR: ENV VAR NOT FOUND, DEFAULT NOT DEFINED", false, "n/a", "n/a", unresolved})
 			} else {
-				variableTable.AppendRow(table.Row{varKey, varData.Default, varData.Required, varData.Secret, true})
+				variableTable.AppendRow(table.Row{varKey, maskSecret(varData.Default, varData.Secret), varData.Required, varData.Secret, true, VariableSource{Kind: SourceDefault}})
 			}
 		}
 	}
@@ -147,16 +196,26 @@ func showAllComponents(tomlData *SpinTOML, envVars map[string]string) string {
 		outputString += "\n\n" + variableTable.Render()
 	}
 
+	if violations := validateVariableConstraints(tomlData, resolveVariables(tomlData, envVars)); len(violations) > 0 {
+		outputString += "\n\n" + showConstraintViolations(violations)
+	}
+
 	return outputString
 }
 
 // showSpecificComponent will show several tables with details about a specific component
-func showSpecificComponent(tomlData *SpinTOML, envVars map[string]string, componentName string) (string, error) {
+func showSpecificComponent(tomlData *SpinTOML, envVars map[string]VariableValue, componentName string) (string, error) {
 	componentData, ok := tomlData.Component[componentName]
 	if !ok {
 		return "", fmt.Errorf("component %q does not exist", componentName)
 	}
 
+	// Set any missing default value, without modifying the envVars map
+	resolvedVars := resolveVariables(tomlData, envVars)
+
+	// Secrets are masked before the templates are parsed, so they are also hidden inside substituted values
+	values := maskSecrets(tomlData, resolvedVars)
+
 	// Redis trigger table
 	redisTable := table.NewWriter()
 	redisTable.SetTitle("Redis Triggers")
@@ -174,7 +233,7 @@ func showSpecificComponent(tomlData *SpinTOML, envVars map[string]string, compon
 				address = redisTrigger.Address
 			}
 
-			redisTable.AppendRow(table.Row{address, redisTrigger.Channel})
+			redisTable.AppendRow(table.Row{resolveField(address, values), resolveField(redisTrigger.Channel, values)})
 		}
 	}
 
@@ -189,7 +248,7 @@ func showSpecificComponent(tomlData *SpinTOML, envVars map[string]string, compon
 			var route string
 			if HTTPTrigger.Route.String != "" { // If the route is a string, handle accordingly
 				// Prepending the application base route (even if blank)
-				route = tomlData.Application.Trigger.HTTP.Base + HTTPTrigger.Route.String
+				route = fullRoute(tomlData.Application.Trigger.HTTP.Base, HTTPTrigger.Route.String)
 			} else if HTTPTrigger.Route.Struct != nil && HTTPTrigger.Route.Struct.Private { // If the route is private, handle accordingly
 				route = "Private"
 			}
@@ -198,58 +257,43 @@ func showSpecificComponent(tomlData *SpinTOML, envVars map[string]string, compon
 				HTTPTrigger.Executor.Type = "spin"
 			}
 
-			HTTPTable.AppendRow(table.Row{route, HTTPTrigger.Executor.Type})
-		}
-	}
-
-	// Other trigger table
-	otherTable := table.NewWriter()
-	otherTable.SetTitle("Other Triggers")
-	var countOther int // This is used to ensure tables with no data are not printed
-	for _, otherTrigger := range tomlData.Trigger.Other {
-		if otherTrigger.Component == componentName {
-			countOther++
-			// Fixing the formatting of this table by ensuring each
-			// row text length is not shorter than the title length
-			lenDiff := len(otherTrigger.TriggerType) - len("Other Triggers")
-			if lenDiff < 0 {
-				for i := lenDiff; i <= 0; i++ {
-					otherTrigger.TriggerType += " "
-				}
+			// The WAGI executor settings are shown below the type, as they only apply to WAGI components
+			executor := HTTPTrigger.Executor.Type
+			if HTTPTrigger.Executor.Entrypoint != "" {
+				executor += "\nentrypoint: " + resolveField(HTTPTrigger.Executor.Entrypoint, values)
 			}
-			otherTable.AppendRow(table.Row{otherTrigger.TriggerType})
+			if HTTPTrigger.Executor.Argv != "" {
+				executor += "\nargv: " + resolveField(HTTPTrigger.Executor.Argv, values)
+			}
+
+			HTTPTable.AppendRow(table.Row{route, executor})
 		}
 	}
 
+	// Plugin trigger tables
+	otherTables := showOtherTriggers(tomlData, componentName, resolvedVars)
+
 	// Variables table
 	variableTable := table.NewWriter()
 	variableTable.SetTitle("Variables")
-	variableTable.AppendHeader(table.Row{"var_key", "var_value"})
-
-	// Set any missing default value in the envVars map
-	for varKey, varData := range tomlData.Variables {
-		var matchExists bool
-		for envKey := range envVars {
-			if varKey == envKey {
-				matchExists = true
-			}
-		}
-
-		if !matchExists {
-			if varData.Default != "" {
-				envVars[varKey] = varData.Default
-			}
-		}
-	}
+	variableTable.AppendHeader(table.Row{"var_key", "var_value", "source"})
 
 	// Parse the component variable templates
 	for compVarKey, compVarValue := range tomlData.Component[componentName].Variables {
-		parsedVal, err := parseComponentVar(compVarValue, envVars)
+		parsedVal, err := parseComponentVar(compVarValue, values)
 		if err != nil {
-			return "", err
+			parsedVal = "ERR: " + err.Error()
 		}
 
-		variableTable.AppendRow(table.Row{compVarKey, parsedVal})
+		variableTable.AppendRow(table.Row{compVarKey, parsedVal, explainTemplateRefs(compVarValue, resolvedVars)})
+	}
+
+	// Environment table
+	environmentTable := table.NewWriter()
+	environmentTable.SetTitle("Environment")
+	environmentTable.AppendHeader(table.Row{"env_key", "env_value"})
+	for _, envKey := range sortedKeys(componentData.Environment) {
+		environmentTable.AppendRow(table.Row{envKey, resolveField(componentData.Environment[envKey], values)})
 	}
 
 	// Outbound resources table
@@ -258,7 +302,7 @@ func showSpecificComponent(tomlData *SpinTOML, envVars map[string]string, compon
 	outboundTable.AppendHeader(table.Row{"Type", "Value"})
 
 	for _, obHost := range componentData.AllowedOutboundHosts {
-		outboundTable.AppendRow(table.Row{"Outbound Host", obHost})
+		outboundTable.AppendRow(table.Row{"Outbound Host", resolveField(obHost, values)})
 	}
 	for _, kvStore := range componentData.KeyValueStores {
 		outboundTable.AppendRow(table.Row{"KV", kvStore})
@@ -274,6 +318,10 @@ func showSpecificComponent(tomlData *SpinTOML, envVars map[string]string, compon
 	var annotations []string
 	annotations = append(annotations, "* Name: "+componentName)
 
+	if componentData.InlineTrigger != "" {
+		annotations = append(annotations, "* Defined Inline: [[trigger."+componentData.InlineTrigger+"]]")
+	}
+
 	if componentData.Description != "" {
 		annotations = append(annotations, "* Description: "+componentData.Description)
 	}
@@ -306,108 +354,188 @@ func showSpecificComponent(tomlData *SpinTOML, envVars map[string]string, compon
 		outputString += "\n\n" + redisTable.Render()
 	}
 
-	if countOther > 0 {
-		outputString += "\n\n" + otherTable.Render()
+	for _, otherTable := range otherTables {
+		outputString += "\n\n" + otherTable
 	}
 
 	if len(tomlData.Component[componentName].Variables) > 0 {
 		outputString += "\n\n" + variableTable.Render()
 	}
 
+	if len(componentData.Environment) > 0 {
+		outputString += "\n\n" + environmentTable.Render()
+	}
+
+	if len(componentData.Dependencies) > 0 {
+		outputString += "\n\nDependencies\n" + showComponentDependencies(componentName, componentData)
+	}
+
 	return outputString, nil
 }
 
+// The text shown in place of the value of a secret variable
+const secretMask = "********"
+
+// maskSecret returns the mask in place of a secret value, unless RevealSecrets is set
+func maskSecret(value string, secret bool) string {
+	if secret && !RevealSecrets && value != "" {
+		return secretMask
+	}
+
+	return value
+}
+
+// maskSecrets returns a copy of the variables with the value of every secret variable masked, unless RevealSecrets is set
+func maskSecrets(tomlData *SpinTOML, vars map[string]VariableValue) map[string]VariableValue {
+	masked := make(map[string]VariableValue, len(vars))
+	for key, value := range vars {
+		value.Value = maskSecret(value.Value, tomlData.Variables[key].Secret)
+		masked[key] = value
+	}
+
+	return masked
+}
+
 func parseSpinToml(filePath string) (*SpinTOML, error) {
+	// Legacy version 1 manifests have a different shape, so they are mapped into the version 2 model
+	var version manifestVersion
+	if _, err := toml.DecodeFile(filePath, &version); err != nil {
+		return nil, err
+	}
+
+	if version.isV1() {
+		v1, _, err := parseSpinTomlV1(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		// The notes only matter when migrating the manifest
+		tomlData, _, err := convertV1(v1)
+		return tomlData, err
+	}
+
 	var tomlFile *SpinTOML
 	if _, err := toml.DecodeFile(filePath, &tomlFile); err != nil {
 		return nil, err
 	}
 
+	mergeInlineComponents(tomlFile)
+
 	return tomlFile, nil
 }
 
-func parseComponentVar(varString string, envVars map[string]string) (string, error) {
-	// This finds any substring that begins with "{{" and ends with "}}", regardless of whitespace in between
-	re := regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+// mergeInlineComponents moves the components defined inline in triggers into the component map,
+// so every view can treat them like any other component.
+// If the synthesized name is already taken by a "[component.<name>]" table, a numeric suffix is added.
+func mergeInlineComponents(tomlData *SpinTOML) {
+	if len(tomlData.Trigger.Inline) > 0 && tomlData.Component == nil {
+		tomlData.Component = make(map[string]Component)
+	}
+
+	for _, inline := range tomlData.Trigger.Inline {
+		name := inline.Name
+		for i := 2; ; i++ {
+			if _, exists := tomlData.Component[name]; !exists {
+				break
+			}
+			name = fmt.Sprintf("%s-%d", inline.Name, i)
+		}
+
+		inline.Component.InlineTrigger = inline.TriggerType
+		tomlData.Component[name] = inline.Component
+
+		switch inline.TriggerType {
+		case "http":
+			tomlData.Trigger.HTTP[inline.Index].Component = name
+		case "redis":
+			tomlData.Trigger.Redis[inline.Index].Component = name
+		default:
+			tomlData.Trigger.Other[inline.Index].Component = name
+		}
+	}
+
+	tomlData.Trigger.Inline = nil
+}
+
+// templateVarRefs returns the names of the variables referenced by a component variable template.
+// If the template is invalid, only the references before the error are returned.
+func templateVarRefs(varString string) []string {
+	parts, _ := parseTemplate(varString)
 
-	// This standardizes the formatting of the strings to {{ .key }} so the template engine can parse the templates to variables
-	varString = re.ReplaceAllStringFunc(varString, func(match string) string {
-		trimmedVar := strings.TrimSpace(match[2 : len(match)-2]) // extract and trim the variable name
-		return fmt.Sprintf("{{ .%s }}", trimmedVar)
-	})
+	var refs []string
+	for _, part := range parts {
+		if part.Variable != "" {
+			refs = append(refs, part.Variable)
+		}
+	}
 
-	tmpl, err := template.New("tmpl").Parse(varString)
+	return refs
+}
+
+// parseComponentVar substitutes the values of the variables referenced by a component variable template
+func parseComponentVar(varString string, envVars map[string]VariableValue) (string, error) {
+	result, err := resolveTemplate(varString, envVars)
 	if err != nil {
-		return "", fmt.Errorf("error parsing template: %v", err)
+		return "", fmt.Errorf("error parsing template %q: %w", varString, err)
 	}
 
-	var result strings.Builder
-	// Use the template engine to substitute
-	if err := tmpl.Execute(&result, envVars); err != nil {
-		return "", fmt.Errorf("error executing template: %v", err)
+	return result, nil
+}
+
+// parseEnvFiles reads the Spin variables from several ".env" files, with the values in later files
+// taking precedence over those in earlier files. If no files are given, the process environment is used instead.
+func parseEnvFiles(filePaths []string) (map[string]VariableValue, error) {
+	if len(filePaths) == 0 {
+		return parseEnvVars("")
 	}
 
-	return result.String(), nil
+	envVars := make(map[string]VariableValue)
+	for _, filePath := range filePaths {
+		fileVars, err := parseEnvVars(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		maps.Copy(envVars, fileVars)
+	}
+
+	return envVars, nil
 }
 
-func parseEnvVars(filePath string) (map[string]string, error) {
-	envVars := make(map[string]string)
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-
-	processEnvVar := func(envVar string) {
-		parts := strings.SplitN(envVar, "=", 2)
-		if len(parts) == 2 && strings.HasPrefix(parts[0], "SPIN_VARIABLE_") {
-			// removing the "SPIN_VARIABLE_" prefix and setting it to lowercase
-			parts[0] = strings.ToLower(strings.Split(parts[0], "SPIN_VARIABLE_")[1])
-			mu.Lock()
-			envVars[parts[0]] = parts[1]
-			mu.Unlock()
+func parseEnvVars(filePath string) (map[string]VariableValue, error) {
+	envVars := make(map[string]VariableValue)
+
+	processEnvVar := func(key, value string, source VariableSource) {
+		if !strings.HasPrefix(key, "SPIN_VARIABLE_") {
+			return
 		}
+
+		// removing the "SPIN_VARIABLE_" prefix and setting it to lowercase
+		key = strings.ToLower(strings.TrimPrefix(key, "SPIN_VARIABLE_"))
+		if previous, ok := envVars[key]; ok && previous.Source.Kind == SourceDotenv {
+			// Duplicates are resolved in file order, so the last value wins
+			fmt.Fprintf(os.Stderr, "Warning: %s sets the variable %q again, overriding the value from line %d\n", source, key, previous.Source.Line)
+		}
+		envVars[key] = VariableValue{Value: value, Source: source}
 	}
 
 	if filePath == "" {
-		vars := os.Environ()
-
-		for _, v := range vars {
-			wg.Add(1)
-			go func(envVar string) {
-				defer wg.Done()
-				processEnvVar(envVar)
-			}(v)
+		for _, envVar := range os.Environ() {
+			key, value, ok := strings.Cut(envVar, "=")
+			if ok {
+				processEnvVar(key, value, VariableSource{Kind: SourceProcessEnv})
+			}
 		}
 	} else {
-		if !strings.HasSuffix(filePath, ".env") {
-			return nil, fmt.Errorf("the path provided appears not to be a \".env\" file: %q", filePath)
-		}
-
-		file, err := os.Open(filePath)
+		entries, err := readDotenvFile(filePath)
 		if err != nil {
 			return nil, err
 		}
-		defer file.Close()
-
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			line := scanner.Text()
-			// Ignore comments and empty lines
-			line = strings.TrimSpace(line)
-			if len(line) == 0 || strings.HasPrefix(line, "#") {
-				continue
-			}
-
-			wg.Add(1)
-			go func(envVar string) {
-				defer wg.Done()
-				processEnvVar(envVar)
-			}(line)
-		}
 
-		if err := scanner.Err(); err != nil {
-			return nil, err
+		for _, entry := range entries {
+			processEnvVar(entry.Key, entry.Value, VariableSource{Kind: SourceDotenv, File: filePath, Line: entry.Line})
 		}
 	}
 
-	wg.Wait()
 	return envVars, nil
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -9,14 +10,14 @@ func TestParseEnvVars(t *testing.T) {
 	tests := []struct {
 		name        string
 		EnvFilePath string
-		want        map[string]string
+		want        map[string]VariableValue
 	}{{
 		name:        "test_env_parsing",
 		EnvFilePath: "../test_data/test.env",
-		want: map[string]string{
-			"test_var":             "test",
-			"secret_var":           "secret",
-			"override_default_var": "overridden_val",
+		want: map[string]VariableValue{
+			"test_var":             {Value: "test", Source: VariableSource{Kind: SourceDotenv, File: "../test_data/test.env", Line: 3}},
+			"secret_var":           {Value: "secret", Source: VariableSource{Kind: SourceDotenv, File: "../test_data/test.env", Line: 4}},
+			"override_default_var": {Value: "overridden_val", Source: VariableSource{Kind: SourceDotenv, File: "../test_data/test.env", Line: 5}},
 		},
 	}}
 
@@ -90,3 +91,133 @@ func TestParseComponentVar(t *testing.T) {
 		}
 	}
 }
+
+func TestShowSpecificComponentMasksSecrets(t *testing.T) {
+	tomlData, err := parseSpinToml("../test_data/spin.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	envVars, err := parseEnvVars("../test_data/test.env")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		revealSecrets bool
+		want          string
+		notWant       string
+	}{
+		{
+			name:    "masked_by_default",
+			want:    "This is the secret_var: " + secretMask,
+			notWant: "This is the secret_var: secret",
+		},
+		{
+			name:          "revealed_with_flag",
+			revealSecrets: true,
+			want:          "This is the secret_var: secret",
+			notWant:       secretMask,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RevealSecrets = tt.revealSecrets
+			defer func() { RevealSecrets = false }()
+
+			got, err := showSpecificComponent(tomlData, envVars, "number-one")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("expected output to contain %q:\n%s", tt.want, got)
+			}
+			if strings.Contains(got, tt.notWant) {
+				t.Errorf("expected output not to contain %q:\n%s", tt.notWant, got)
+			}
+			if strings.Contains(showAllComponents(tomlData, envVars), "| secret ") != tt.revealSecrets {
+				t.Errorf("unexpected secret visibility in the variables table")
+			}
+		})
+	}
+}
+
+func TestShowSpecificComponentExecutorAndEnvironment(t *testing.T) {
+	tomlData := &SpinTOML{
+		Variables: map[string]Variable{
+			"script": {Default: "index.php"},
+			"token":  {Secret: true},
+		},
+		Trigger: Trigger{
+			HTTP: []HTTPTrigger{{
+				Route:     Route{String: "/cgi/..."},
+				Component: "legacy",
+				Executor:  Executor{Type: "wagi", Entrypoint: "main", Argv: "${SCRIPT_NAME} {{ script }} ${ARGS}"},
+			}},
+		},
+		Component: map[string]Component{
+			"legacy": {
+				Source:      Source{String: "legacy.wasm"},
+				Environment: map[string]string{"MODE": "cgi", "TOKEN": "{{ token }}", "BROKEN": "{{ missing }}"},
+			},
+		},
+	}
+
+	envVars := map[string]VariableValue{
+		"token": {Value: "s3cr3t", Source: VariableSource{Kind: SourceProcessEnv}},
+	}
+
+	got, err := showSpecificComponent(tomlData, envVars, "legacy")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"entrypoint: main",
+		"argv: ${SCRIPT_NAME} index.php ${ARGS}",
+		"| MODE    | cgi",
+		"| TOKEN   | " + secretMask,
+		`| BROKEN  | {{ missing }} (ERR: offset 0: variable "missing" is not declared)`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestShowSpecificComponentBasePath(t *testing.T) {
+	tests := []struct {
+		name string
+		base string
+		want string
+	}{
+		{name: "no_base", base: "", want: "| /... "},
+		{name: "base", base: "/api", want: "| /api/... "},
+		// The trailing slash of the base must not be doubled
+		{name: "base_with_trailing_slash", base: "/api/", want: "| /api/... "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tomlData := &SpinTOML{
+				Application: Application{Trigger: ApplicationTrigger{HTTP: ApplicationTriggerHTTP{Base: tt.base}}},
+				Trigger:     Trigger{HTTP: []HTTPTrigger{{Route: Route{String: "/..."}, Component: "api"}}},
+				Component:   map[string]Component{"api": {Source: Source{String: "api.wasm"}}},
+			}
+
+			got, err := showSpecificComponent(tomlData, nil, "api")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("expected output to contain %q:\n%s", tt.want, got)
+			}
+			if strings.Contains(got, "//") {
+				t.Errorf("expected no doubled slash in the route:\n%s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// templatePart is a piece of a Spin variable template: either literal text or a reference to a variable
+type templatePart struct {
+	Literal  string
+	Variable string
+	// The byte offset of the part within the template
+	Offset int
+}
+
+// TemplateError is an error in a Spin variable template, at a byte offset within the template
+type TemplateError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("offset %d: %s", e.Offset, e.Msg)
+}
+
+// parseTemplate splits a Spin variable template (e.g. "https://{{ host }}/api") into its parts.
+// A variable reference is a name of lowercase letters, digits and underscores, starting with a letter,
+// wrapped in "{{" and "}}" with optional whitespace. Any other text, including single braces, is literal.
+// If the template is invalid, the parts parsed before the error are returned along with a *TemplateError.
+func parseTemplate(template string) ([]templatePart, error) {
+	var parts []templatePart
+	offset := 0
+	for offset < len(template) {
+		start := strings.Index(template[offset:], "{{")
+		if start < 0 {
+			parts = append(parts, templatePart{Literal: template[offset:], Offset: offset})
+			break
+		}
+
+		start += offset
+		if start > offset {
+			parts = append(parts, templatePart{Literal: template[offset:start], Offset: offset})
+		}
+
+		end := strings.Index(template[start+2:], "}}")
+		if end < 0 {
+			return parts, &TemplateError{Offset: start, Msg: `unclosed "{{"`}
+		}
+
+		end += start + 2
+		inner := template[start+2 : end]
+		if nested := strings.Index(inner, "{{"); nested >= 0 {
+			return parts, &TemplateError{Offset: start + 2 + nested, Msg: `unexpected "{{" inside a variable reference`}
+		}
+		name := strings.TrimSpace(inner)
+		if !isTemplateVarName(name) {
+			// Pointing the error at the name itself, rather than the opening braces
+			nameOffset := start + 2 + strings.Index(inner, name)
+			if name == "" {
+				nameOffset = start
+			}
+			return parts, &TemplateError{Offset: nameOffset, Msg: fmt.Sprintf("invalid variable name %q", name)}
+		}
+
+		parts = append(parts, templatePart{Variable: name, Offset: start})
+		offset = end + 2
+	}
+
+	return parts, nil
+}
+
+// isTemplateVarName reports whether the string is a valid Spin variable name
+func isTemplateVarName(name string) bool {
+	if name == "" || name[0] < 'a' || name[0] > 'z' {
+		return false
+	}
+
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// resolveTemplate substitutes the values of the variables referenced by a Spin variable template.
+// The variables are the resolved top-level variables (see resolveVariables).
+// Referencing a variable that isn't declared, or that is declared but has no value, is an error.
+func resolveTemplate(template string, vars map[string]VariableValue) (string, error) {
+	parts, err := parseTemplate(template)
+	if err != nil {
+		return "", err
+	}
+
+	var result strings.Builder
+	for _, part := range parts {
+		if part.Variable == "" {
+			result.WriteString(part.Literal)
+			continue
+		}
+
+		value, ok := vars[part.Variable]
+		if !ok {
+			return "", &TemplateError{Offset: part.Offset, Msg: fmt.Sprintf("variable %q is not declared", part.Variable)}
+		}
+		if value.Source.Kind == SourceUnresolved {
+			return "", &TemplateError{Offset: part.Offset, Msg: fmt.Sprintf("variable %q has no value or default", part.Variable)}
+		}
+		result.WriteString(value.Value)
+	}
+
+	return result.String(), nil
+}
+
+// resolveField resolves a templated manifest field for display. If the template can't be resolved,
+// the raw value is kept and the error is appended to it.
+func resolveField(template string, vars map[string]VariableValue) string {
+	resolved, err := resolveTemplate(template, vars)
+	if err != nil {
+		return fmt.Sprintf("%s (ERR: %v)", template, err)
+	}
+
+	return resolved
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolveTemplate(t *testing.T) {
+	vars := map[string]VariableValue{
+		"host":    {Value: "example.com"},
+		"port":    {Value: "8080"},
+		"api_key": {Value: "secret"},
+		// Declared, but without a value
+		"region": {Source: VariableSource{Kind: SourceUnresolved}},
+	}
+
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		wantOffset int    // Only checked if wantErr is set
+		wantMsg    string // Only checked if set
+		wantErr    bool
+	}{
+		{name: "single_variable", template: "https://{{ host }}", want: "https://example.com"},
+		{name: "no_spaces", template: "{{host}}:{{port}}", want: "example.com:8080"},
+		{name: "literal_braces", template: "{ \"key\": \"{{ api_key }}\" } }}", want: "{ \"key\": \"secret\" } }}"},
+		{name: "go_template_syntax_is_literal", template: "{{{ host }}", wantErr: true, wantOffset: 2},
+		{name: "go_template_field", template: "value: {{ .Host }}", wantErr: true, wantOffset: 10},
+		{name: "go_template_pipeline", template: "{{ host | printf }}", wantErr: true, wantOffset: 3},
+		{name: "undeclared_variable", template: "{{ host }}/{{ missing }}", wantErr: true, wantOffset: 11, wantMsg: `variable "missing" is not declared`},
+		{name: "variable_without_value", template: "{{ region }}", wantErr: true, wantOffset: 0, wantMsg: `variable "region" has no value or default`},
+		{name: "nested_braces", template: "{{{{ host }}", wantErr: true, wantOffset: 2, wantMsg: `unexpected "{{" inside a variable reference`},
+		{name: "unclosed", template: "abc {{ host", wantErr: true, wantOffset: 4},
+		{name: "empty_reference", template: "abc {{ }}", wantErr: true, wantOffset: 4},
+		{name: "uppercase_name", template: "{{ HOST }}", wantErr: true, wantOffset: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveTemplate(tt.template, vars)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got != tt.want {
+					t.Errorf("resolveTemplate(%q) = %q, want %q", tt.template, got, tt.want)
+				}
+				return
+			}
+
+			var templateErr *TemplateError
+			if !errors.As(err, &templateErr) {
+				t.Fatalf("expected a *TemplateError, got %v", err)
+			}
+			if templateErr.Offset != tt.wantOffset {
+				t.Errorf("expected the error at offset %d, got %d (%v)", tt.wantOffset, templateErr.Offset, err)
+			}
+			if tt.wantMsg != "" && templateErr.Msg != tt.wantMsg {
+				t.Errorf("expected the error %q, got %q", tt.wantMsg, templateErr.Msg)
+			}
+		})
+	}
+}
+
+func TestTemplateVarRefs(t *testing.T) {
+	got := templateVarRefs("{{ a }} {b} {{c_1}} {{ .d }} {{ e }}")
+	want := []string{"a", "c_1"}
+
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("templateVarRefs() = %v, want %v", got, want)
+	}
+}
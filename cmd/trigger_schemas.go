@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// TriggerSetting is a setting in the "[[trigger.<type>]]" table of a trigger provided by a plugin
+type TriggerSetting struct {
+	Key      string
+	Required bool
+	// check returns a description of what is wrong with the value, or blank if the value is valid.
+	// The description uses shown in place of the value, so secrets substituted into it stay masked.
+	check func(value any, shown string) string
+}
+
+// TriggerSchema describes the settings of a trigger type provided by a plugin
+type TriggerSchema struct {
+	// The title of the table the triggers are shown in
+	Title    string
+	Settings []TriggerSetting
+}
+
+// triggerSchemas are the settings of well-known plugin triggers, keyed by trigger type.
+// Triggers of any other type are shown with their raw settings, without validation.
+var triggerSchemas = map[string]TriggerSchema{
+	"cron": {Title: "Cron Triggers", Settings: []TriggerSetting{
+		{Key: "cron_expression", Required: true, check: checkCronExpression},
+	}},
+	"sqs": {Title: "SQS Triggers", Settings: []TriggerSetting{
+		{Key: "queue_url", Required: true, check: checkHTTPURL},
+		{Key: "max_messages", check: checkIntRange(1, 10)},
+		{Key: "idle_wait_seconds", check: checkIntRange(0, math.MaxInt)},
+		{Key: "system_attributes", check: checkStringList},
+		{Key: "message_attributes", check: checkStringList},
+	}},
+	"mqtt": {Title: "MQTT Triggers", Settings: []TriggerSetting{
+		{Key: "topic", Required: true, check: checkNonEmptyString},
+		{Key: "qos", Required: true, check: checkIntRange(0, 2)},
+	}},
+	// The command trigger runs the component once, so it has no settings
+	"command": {Title: "Command Triggers"},
+	"kafka": {Title: "Kafka Triggers", Settings: []TriggerSetting{
+		{Key: "topic", Required: true, check: checkNonEmptyString},
+		{Key: "group_id", check: checkNonEmptyString},
+	}},
+}
+
+func checkNonEmptyString(value any, shown string) string {
+	str, ok := value.(string)
+	if !ok {
+		return fmt.Sprintf("expected a string, got %T", value)
+	}
+	if strings.TrimSpace(str) == "" {
+		return "must not be empty"
+	}
+
+	return ""
+}
+
+func checkStringList(value any, shown string) string {
+	list, ok := value.([]any)
+	if !ok {
+		return fmt.Sprintf("expected a list of strings, got %T", value)
+	}
+	for _, item := range list {
+		if _, ok := item.(string); !ok {
+			return fmt.Sprintf("expected a list of strings, got an item of type %T", item)
+		}
+	}
+
+	return ""
+}
+
+func checkHTTPURL(value any, shown string) string {
+	if problem := checkNonEmptyString(value, shown); problem != "" {
+		return problem
+	}
+
+	parsed, err := url.Parse(value.(string))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Sprintf("%q is not an HTTP(S) URL", shown)
+	}
+
+	return ""
+}
+
+// checkIntRange returns a check for an integer between min and max (inclusive).
+// Some plugins take numbers as strings (e.g. MQTT's qos = "1"), so numeric strings are accepted too.
+func checkIntRange(min, max int) func(value any, shown string) string {
+	return func(value any, shown string) string {
+		var number int64
+		switch v := value.(type) {
+		case int64:
+			number = v
+		case string:
+			parsed, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return fmt.Sprintf("expected an integer, got %q", shown)
+			}
+			number = parsed
+		default:
+			return fmt.Sprintf("expected an integer, got %T", value)
+		}
+
+		if number < int64(min) || number > int64(max) {
+			if max == math.MaxInt {
+				return fmt.Sprintf("%s must be at least %d", shown, min)
+			}
+			return fmt.Sprintf("%s must be between %d and %d", shown, min, max)
+		}
+
+		return ""
+	}
+}
+
+// checkCronExpression checks that the expression can be parsed, see parseCron
+func checkCronExpression(value any, shown string) string {
+	if problem := checkNonEmptyString(value, shown); problem != "" {
+		return problem
+	}
+
+	if _, err := parseCron(value.(string)); err != nil {
+		// The parse errors quote parts of the expression, so they are only given if nothing in it is masked
+		if shown != value {
+			return fmt.Sprintf("%q is not a valid cron expression", shown)
+		}
+		return err.Error()
+	}
+
+	return ""
+}
+
+// mergeTriggerSettings returns the settings of a trigger, falling back to the "[application.trigger.<type>]" settings
+// for the ones the trigger doesn't set. This is the same fallback as for the application Redis address.
+func mergeTriggerSettings(appSettings, triggerSettings map[string]any) map[string]any {
+	merged := make(map[string]any, len(appSettings)+len(triggerSettings))
+	for key, value := range appSettings {
+		merged[key] = value
+	}
+	for key, value := range triggerSettings {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// validateTriggerSettings checks the settings of a plugin trigger against its schema, if the trigger type is known.
+// Required settings can also come from the application-level settings of the trigger type.
+// Templated string settings are resolved with the variables before they are checked,
+// and resolved with shownVars (e.g. with secrets masked) for the messages.
+func validateTriggerSettings(trigger OtherTrigger, appSettings map[string]any, vars, shownVars map[string]VariableValue) []string {
+	schema, ok := triggerSchemas[trigger.TriggerType]
+	if !ok {
+		return nil
+	}
+
+	var problems []string
+	known := make(map[string]bool, len(schema.Settings))
+	for _, setting := range schema.Settings {
+		known[setting.Key] = true
+
+		value, ok := mergeTriggerSettings(appSettings, trigger.Config)[setting.Key]
+		if !ok {
+			if setting.Required {
+				problems = append(problems, fmt.Sprintf("missing required setting %q", setting.Key))
+			}
+			continue
+		}
+
+		shown := fmt.Sprint(value)
+		if template, ok := value.(string); ok {
+			resolved, err := resolveTemplate(template, vars)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", setting.Key, err))
+				continue
+			}
+			value = resolved
+			shown = resolveField(template, shownVars)
+		}
+
+		if problem := setting.check(value, shown); problem != "" {
+			problems = append(problems, fmt.Sprintf("%s: %s", setting.Key, problem))
+		}
+	}
+
+	for _, key := range sortedKeys(trigger.Config) {
+		if !known[key] {
+			problems = append(problems, fmt.Sprintf("unknown setting %q", key))
+		}
+	}
+
+	return problems
+}
+
+// formatTriggerValue formats a raw trigger setting for display, resolving templated strings
+func formatTriggerValue(value any, vars map[string]VariableValue) string {
+	switch v := value.(type) {
+	case string:
+		return resolveField(v, vars)
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatTriggerValue(item, vars)
+		}
+		return strings.Join(items, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// applicationTriggerSettings describes the "[application.trigger.<type>]" settings of every trigger type,
+// keyed by trigger type, e.g. "region = us-west-2, wait = 5" for "sqs"
+func applicationTriggerSettings(tomlData *SpinTOML, vars map[string]VariableValue) map[string]string {
+	appTriggers := make(map[string]map[string]any)
+	if base := tomlData.Application.Trigger.HTTP.Base; base != "" {
+		appTriggers["http"] = map[string]any{"base": base}
+	}
+	if address := tomlData.Application.Trigger.Redis.Address; address != "" {
+		appTriggers["redis"] = map[string]any{"address": address}
+	}
+	for triggerType, settings := range tomlData.Application.Trigger.Other {
+		appTriggers[triggerType] = settings
+	}
+
+	descriptions := make(map[string]string, len(appTriggers))
+	for triggerType, appSettings := range appTriggers {
+		var settings []string
+		for _, key := range sortedKeys(appSettings) {
+			settings = append(settings, key+" = "+formatTriggerValue(appSettings[key], vars))
+		}
+		descriptions[triggerType] = strings.Join(settings, ", ")
+	}
+
+	return descriptions
+}
+
+// showOtherTriggers will display a table for every plugin trigger type of the component.
+// Known trigger types get a column per setting and their problems as the caption,
+// while every setting of an unknown type is shown as a raw key/value pair.
+// The settings are validated with the variables, but secrets are masked in everything displayed.
+func showOtherTriggers(tomlData *SpinTOML, componentName string, vars map[string]VariableValue) []string {
+	shownVars := maskSecrets(tomlData, vars)
+
+	byType := make(map[string][]OtherTrigger)
+	for _, otherTrigger := range tomlData.Trigger.Other {
+		if otherTrigger.Component == componentName {
+			byType[otherTrigger.TriggerType] = append(byType[otherTrigger.TriggerType], otherTrigger)
+		}
+	}
+
+	var tables []string
+	var unknownTypes []string
+	for _, triggerType := range sortedKeys(byType) {
+		schema, ok := triggerSchemas[triggerType]
+		if !ok {
+			unknownTypes = append(unknownTypes, triggerType)
+			continue
+		}
+
+		appSettings := tomlData.Application.Trigger.Other[triggerType]
+
+		triggerTable := table.NewWriter()
+		triggerTable.SetTitle(schema.Title)
+		// A single narrow column would wrap the title, so the column is made at least as wide as it
+		triggerTable.SetColumnConfigs([]table.ColumnConfig{{Number: 1, WidthMin: len(schema.Title)}})
+
+		var problems []string
+		if len(schema.Settings) == 0 {
+			triggerTable.AppendHeader(table.Row{"settings"})
+			for range byType[triggerType] {
+				triggerTable.AppendRow(table.Row{"none"})
+			}
+		} else {
+			var header table.Row
+			for _, setting := range schema.Settings {
+				header = append(header, setting.Key)
+			}
+			triggerTable.AppendHeader(header)
+
+			for _, otherTrigger := range byType[triggerType] {
+				var row table.Row
+				for _, setting := range schema.Settings {
+					var cell string
+					if value, ok := mergeTriggerSettings(appSettings, otherTrigger.Config)[setting.Key]; ok {
+						cell = formatTriggerValue(value, shownVars)
+					}
+					row = append(row, cell)
+				}
+				triggerTable.AppendRow(row)
+			}
+		}
+
+		for _, otherTrigger := range byType[triggerType] {
+			for _, problem := range validateTriggerSettings(otherTrigger, appSettings, vars, shownVars) {
+				problems = append(problems, "ERR: "+problem)
+			}
+		}
+		if len(problems) > 0 {
+			triggerTable.SetCaption(strings.Join(problems, "\n"))
+		}
+
+		tables = append(tables, triggerTable.Render())
+	}
+
+	if len(unknownTypes) > 0 {
+		otherTable := table.NewWriter()
+		otherTable.SetTitle("Other Triggers")
+		otherTable.AppendHeader(table.Row{"type", "key", "value"})
+		for _, triggerType := range unknownTypes {
+			for _, otherTrigger := range byType[triggerType] {
+				settings := mergeTriggerSettings(tomlData.Application.Trigger.Other[triggerType], otherTrigger.Config)
+				if len(settings) == 0 {
+					otherTable.AppendRow(table.Row{triggerType, "", ""})
+				}
+				for _, key := range sortedKeys(settings) {
+					otherTable.AppendRow(table.Row{triggerType, key, formatTriggerValue(settings[key], shownVars)})
+				}
+			}
+		}
+		tables = append(tables, otherTable.Render())
+	}
+
+	return tables
+}
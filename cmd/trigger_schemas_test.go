@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidateTriggerSettings(t *testing.T) {
+	vars := map[string]VariableValue{
+		"queue_url":   {Value: "https://sqs.us-west-2.amazonaws.com/123456789012/jobs"},
+		"bad_url":     {Value: "not a url"},
+		"secret_url":  {Value: "not a url supersecret"},
+		"secret_cron": {Value: "supersecret"},
+	}
+	// The values substituted into the messages, with the secrets masked
+	shownVars := maskSecrets(&SpinTOML{Variables: map[string]Variable{"secret_url": {Secret: true}, "secret_cron": {Secret: true}}}, vars)
+
+	tests := []struct {
+		name        string
+		trigger     OtherTrigger
+		appSettings map[string]any
+		want        []string
+	}{
+		{
+			name:    "valid_cron",
+			trigger: OtherTrigger{TriggerType: "cron", Config: map[string]any{"cron_expression": "0 */5 * * * *"}},
+		},
+		{
+			name:    "cron_missing_expression",
+			trigger: OtherTrigger{TriggerType: "cron"},
+			want:    []string{`missing required setting "cron_expression"`},
+		},
+		{
+			name:    "cron_too_few_fields",
+			trigger: OtherTrigger{TriggerType: "cron", Config: map[string]any{"cron_expression": "* * *"}},
+			want:    []string{"cron_expression: expected 6 or 7 fields, got 3"},
+		},
+		{
+			name:    "sqs_templated_url",
+			trigger: OtherTrigger{TriggerType: "sqs", Config: map[string]any{"queue_url": "{{ queue_url }}", "max_messages": int64(10)}},
+		},
+		{
+			name:    "sqs_invalid",
+			trigger: OtherTrigger{TriggerType: "sqs", Config: map[string]any{"queue_url": "{{ bad_url }}", "max_messages": int64(11), "system_attributes": "All"}},
+			want: []string{
+				`queue_url: "not a url" is not an HTTP(S) URL`,
+				"max_messages: 11 must be between 1 and 10",
+				"system_attributes: expected a list of strings, got string",
+			},
+		},
+		{
+			name:    "sqs_secret_url",
+			trigger: OtherTrigger{TriggerType: "sqs", Config: map[string]any{"queue_url": "https://{{ secret_url }}"}},
+			want:    []string{`queue_url: "https://********" is not an HTTP(S) URL`},
+		},
+		{
+			name:    "cron_secret_expression",
+			trigger: OtherTrigger{TriggerType: "cron", Config: map[string]any{"cron_expression": "0 {{ secret_cron }} * * * *"}},
+			want:    []string{`cron_expression: "0 ******** * * * *" is not a valid cron expression`},
+		},
+		{
+			name:    "sqs_unknown_variable",
+			trigger: OtherTrigger{TriggerType: "sqs", Config: map[string]any{"queue_url": "{{ missing }}"}},
+			want:    []string{`queue_url: offset 0: variable "missing" is not declared`},
+		},
+		{
+			name:    "mqtt_string_qos",
+			trigger: OtherTrigger{TriggerType: "mqtt", Config: map[string]any{"topic": "sensors/#", "qos": "1"}},
+		},
+		{
+			name:        "mqtt_qos_from_application",
+			trigger:     OtherTrigger{TriggerType: "mqtt", Config: map[string]any{"topic": "sensors/#"}},
+			appSettings: map[string]any{"qos": int64(1), "address": "mqtt://localhost:1883"},
+		},
+		{
+			name:        "mqtt_invalid_qos_from_application",
+			trigger:     OtherTrigger{TriggerType: "mqtt", Config: map[string]any{"topic": "sensors/#"}},
+			appSettings: map[string]any{"qos": int64(5)},
+			want:        []string{"qos: 5 must be between 0 and 2"},
+		},
+		{
+			name:    "mqtt_unknown_setting",
+			trigger: OtherTrigger{TriggerType: "mqtt", Config: map[string]any{"topic": "", "qos": int64(0), "retain": true}},
+			want:    []string{"topic: must not be empty", `unknown setting "retain"`},
+		},
+		{
+			name:    "command_with_setting",
+			trigger: OtherTrigger{TriggerType: "command", Config: map[string]any{"args": "--verbose"}},
+			want:    []string{`unknown setting "args"`},
+		},
+		{
+			name:    "unknown_type_is_not_validated",
+			trigger: OtherTrigger{TriggerType: "webhook-relay", Config: map[string]any{"anything": int64(1)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateTriggerSettings(tt.trigger, tt.appSettings, vars, shownVars)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("validateTriggerSettings() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestShowOtherTriggers(t *testing.T) {
+	tomlData, err := parseSpinToml("../test_data/spin_triggers.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := strings.Join(showOtherTriggers(tomlData, "batch", resolveVariables(tomlData, nil)), "\n")
+	for _, want := range []string{
+		"| Command Triggers |",
+		"| 0 */5 * * * *   |",
+		"| https://sqs.us-west-2.amazonaws.com/123456789012/jobs | 5            |",
+		"ERR: qos: 3 must be between 0 and 2",
+		`ERR: unknown setting "retain"`,
+		"| webhook-relay | endpoint | https://relay.example.com |",
+		// The application-level settings are merged in, with the trigger settings taking precedence
+		"| webhook-relay | region   | eu                        |",
+		"| webhook-relay | retries  | 3                         |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the output to contain %q, got:\n%s", want, got)
+		}
+	}
+
+	header := showAllComponents(tomlData, nil)
+	for _, want := range []string{
+		"* Trigger Settings (mqtt): address = mqtt://localhost:1883, username = blueprint",
+		"* Trigger Settings (webhook-relay): region = eu, retries = 1",
+	} {
+		if !strings.Contains(header, want) {
+			t.Errorf("expected the application header to contain %q, got:\n%s", want, header)
+		}
+	}
+
+	findings := lintApp(tomlData, nil)
+	want := []LintFinding{
+		{Check: "trigger-settings", Subject: "trigger.mqtt (component batch)", Message: "qos: 3 must be between 0 and 2"},
+		{Check: "trigger-settings", Subject: "trigger.mqtt (component batch)", Message: `unknown setting "retain"`},
+	}
+	if diff := cmp.Diff(want, findings); diff != "" {
+		t.Errorf("lintApp() mismatch (-want +got):\n%s", diff)
+	}
+
+	t.Run("secret_setting", func(t *testing.T) {
+		// A secret is validated with its value, but only shown masked
+		tomlData.Variables["queue_url"] = Variable{Secret: true}
+		envVars := map[string]VariableValue{"queue_url": {Value: "https://sqs.us-west-2.amazonaws.com/123456789012/jobs", Source: VariableSource{Kind: SourceProcessEnv}}}
+
+		got := strings.Join(showOtherTriggers(tomlData, "batch", resolveVariables(tomlData, envVars)), "\n")
+		if strings.Contains(got, "queue_url:") || strings.Contains(got, "sqs.us-west-2") {
+			t.Errorf("expected the secret queue URL to be valid and masked, got:\n%s", got)
+		}
+
+		envVars["queue_url"] = VariableValue{Value: "not a url supersecret", Source: VariableSource{Kind: SourceProcessEnv}}
+		wantFinding := LintFinding{Check: "trigger-settings", Subject: "trigger.sqs (component batch)", Message: `queue_url: "********" is not an HTTP(S) URL`}
+		if findings := lintApp(tomlData, envVars); !slices.Contains(findings, wantFinding) {
+			t.Errorf("expected the lint findings to contain %+v, got %+v", wantFinding, findings)
+		}
+	})
+}
+
+func TestMigrateV1PluginTriggers(t *testing.T) {
+	// The settings of a plugin trigger are kept as they are, including tables and lists
+	v1Path := filepath.Join(t.TempDir(), "spin.toml")
+	v1 := `spin_manifest_version = "1"
+name = "jobs"
+version = "1.0.0"
+trigger = { type = "sqs" }
+
+[[component]]
+id = "batch"
+source = "batch.wasm"
+[component.trigger]
+queue_url = "https://sqs.us-west-2.amazonaws.com/123456789012/jobs"
+max_messages = 5
+system_attributes = ["All"]
+retry = { attempts = 3, backoff = "exponential" }
+`
+	if err := os.WriteFile(v1Path, []byte(v1), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want, err := parseSpinToml(v1Path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(want.Trigger.Other) != 1 || want.Trigger.Other[0].Config["retry"] == nil {
+		t.Fatalf("expected the version 1 manifest to have an SQS trigger with a retry table, got %+v", want.Trigger.Other)
+	}
+
+	migrated, _, err := migrateV1(v1Path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	migratedPath := filepath.Join(t.TempDir(), "spin.toml")
+	if err := os.WriteFile(migratedPath, []byte(migrated), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := parseSpinToml(migratedPath)
+	if err != nil {
+		t.Fatalf("the migrated manifest is invalid: %v\n%s", err, migrated)
+	}
+
+	if diff := cmp.Diff(want.Trigger.Other, got.Trigger.Other); diff != "" {
+		t.Errorf("migrateV1() mismatch (-want +got):\n%s", diff)
+	}
+}
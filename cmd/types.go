@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"reflect"
+	"sort"
 
+	"github.com/BurntSushi/toml"
 	"github.com/go-viper/mapstructure/v2"
 )
 
@@ -19,6 +22,30 @@ type SpinTOML struct {
 
 	// The top-level variables resolved via environment variables
 	Variables map[string]Variable `toml:"variables"`
+
+	// Tool-specific metadata, which Spin itself ignores
+	Tool Tool `toml:"tool"`
+}
+
+// Tool holds the tool-specific top-level "[tool.<tool>]" tables
+type Tool struct {
+	Blueprint BlueprintTool `toml:"blueprint"`
+}
+
+type BlueprintTool struct {
+	// Constraints on the values of the top-level variables, keyed by variable name
+	Variables map[string]VariableConstraint `toml:"variables"`
+}
+
+// VariableConstraint describes the valid values of a top-level variable, in "[tool.blueprint.variables.<name>]"
+type VariableConstraint struct {
+	Description string `toml:"description"`
+	// A regular expression the whole value must match
+	Pattern string   `toml:"pattern"`
+	Enum    []string `toml:"enum"`
+	// A well-known format the value must be in. Only "url" is supported.
+	Format  string `toml:"format"`
+	Example string `toml:"example"`
 }
 
 type Variable struct {
@@ -38,6 +65,42 @@ type Application struct {
 type ApplicationTrigger struct {
 	HTTP  ApplicationTriggerHTTP  `toml:"http"`
 	Redis ApplicationTriggerRedis `toml:"redis"`
+	// The settings of every other trigger type (e.g. "[application.trigger.sqs]"), keyed by trigger type
+	Other map[string]map[string]any
+}
+
+// UnmarshalTOML (for *ApplicationTrigger) is a function that the `toml` package will call when
+// it encounters an application trigger data structure. It must be named UnmarshalTOML, regardless of the type
+func (a *ApplicationTrigger) UnmarshalTOML(rawData any) error {
+	data, ok := rawData.(map[string]any)
+	if !ok {
+		return fmt.Errorf("malformed data, expected map")
+	}
+
+	for _, key := range sortedKeys(data) {
+		settings, ok := data[key].(map[string]any)
+		if !ok {
+			return fmt.Errorf("malformed application trigger %q, expected a table", key)
+		}
+
+		switch key {
+		case "http":
+			if err := mapstructure.Decode(settings, &a.HTTP); err != nil {
+				return fmt.Errorf("failed to map application trigger %q: %w", key, err)
+			}
+		case "redis":
+			if err := mapstructure.Decode(settings, &a.Redis); err != nil {
+				return fmt.Errorf("failed to map application trigger %q: %w", key, err)
+			}
+		default:
+			if a.Other == nil {
+				a.Other = make(map[string]map[string]any)
+			}
+			a.Other[key] = settings
+		}
+	}
+
+	return nil
 }
 
 type ApplicationTriggerHTTP struct {
@@ -52,6 +115,21 @@ type Trigger struct {
 	HTTP  []HTTPTrigger
 	Redis []RedisTrigger
 	Other []OtherTrigger
+
+	// The components defined inline in a trigger table, rather than referenced by name.
+	// They are moved into SpinTOML.Component by parseSpinToml, so this is empty after parsing.
+	Inline []InlineComponent
+}
+
+// InlineComponent is a component defined as an inline table in a trigger, e.g. "component = { source = "main.wasm" }".
+// Such components have no name in the manifest, so one is synthesized for them.
+type InlineComponent struct {
+	// The synthesized name, which the trigger's Component field is set to
+	Name        string
+	TriggerType string
+	// The index of the trigger within the HTTP, Redis or Other triggers, depending on the type
+	Index     int
+	Component Component
 }
 
 type RedisTrigger struct {
@@ -72,6 +150,8 @@ type OtherTrigger struct {
 	// This is a hacky solution for storing the type of the trigger
 	// This is done in the UnmarshalTOML function for the Trigger type.
 	TriggerType string
+	// Every other setting in the trigger table, which depends on the trigger type (e.g. "cron_expression" for cron)
+	Config map[string]any `mapstructure:",remain"`
 }
 
 // UnmarshalTOML (for *Trigger) is a function that the `toml` package will call when
@@ -81,9 +161,21 @@ func (t *Trigger) UnmarshalTOML(rawData any) error {
 	if !ok {
 		return fmt.Errorf("malformed data, expected map")
 	}
-	for key, value := range data {
+	// The trigger types are decoded in sorted order, so the order of the triggers doesn't depend on the map order
+	for _, key := range sortedKeys(data) {
+		value := data[key]
+
+		// Inline components are replaced by a synthesized name, so the rest of the trigger decodes like any other
+		inline, err := extractInlineComponents(key, value)
+		if err != nil {
+			return err
+		}
+
+		// The triggers of this type are appended after the existing ones, so the indexes are offset
+		var offset int
 		switch key {
 		case "http":
+			offset = len(t.HTTP)
 			var httpTriggers []HTTPTrigger
 			decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 				DecodeHook: routeDecodeHook,
@@ -93,9 +185,12 @@ func (t *Trigger) UnmarshalTOML(rawData any) error {
 				return err
 			}
 
-			decoder.Decode(value)
+			if err := decoder.Decode(value); err != nil {
+				return fmt.Errorf("failed to map trigger %q: %w", key, err)
+			}
 			t.HTTP = append(t.HTTP, httpTriggers...)
 		case "redis":
+			offset = len(t.Redis)
 			var redisTriggers []RedisTrigger
 			err := mapstructure.Decode(value, &redisTriggers)
 			if err != nil {
@@ -103,6 +198,7 @@ func (t *Trigger) UnmarshalTOML(rawData any) error {
 			}
 			t.Redis = append(t.Redis, redisTriggers...)
 		default:
+			offset = len(t.Other)
 			var otherTriggers []OtherTrigger
 			err := mapstructure.Decode(value, &otherTriggers)
 			if err != nil {
@@ -116,11 +212,61 @@ func (t *Trigger) UnmarshalTOML(rawData any) error {
 
 			t.Other = append(t.Other, otherTriggers...)
 		}
+
+		for _, inlineComponent := range inline {
+			inlineComponent.Index += offset
+			t.Inline = append(t.Inline, inlineComponent)
+		}
 	}
 
 	return nil
 }
 
+// extractInlineComponents decodes the inline component tables in the raw triggers of a type,
+// replacing each one with a synthesized "inline-<type>-<n>" name, where n counts from 1 in manifest order.
+func extractInlineComponents(triggerType string, rawTriggers any) ([]InlineComponent, error) {
+	// Arrays of tables decode as []map[string]any, while inline arrays of tables decode as []any
+	var triggers []map[string]any
+	switch v := rawTriggers.(type) {
+	case []map[string]any:
+		triggers = v
+	case []any:
+		for _, item := range v {
+			trigger, ok := item.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("malformed trigger %q, expected a table", triggerType)
+			}
+			triggers = append(triggers, trigger)
+		}
+	default:
+		return nil, nil
+	}
+
+	var inline []InlineComponent
+	for i, trigger := range triggers {
+		componentTable, ok := trigger["component"].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		// Round-tripping through TOML, so the custom UnmarshalTOML functions (e.g. for Source) are used
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(componentTable); err != nil {
+			return nil, fmt.Errorf("failed to read the inline component of trigger %q #%d: %w", triggerType, i+1, err)
+		}
+		var component Component
+		if _, err := toml.Decode(buf.String(), &component); err != nil {
+			return nil, fmt.Errorf("failed to read the inline component of trigger %q #%d: %w", triggerType, i+1, err)
+		}
+
+		name := fmt.Sprintf("inline-%s-%d", triggerType, i+1)
+		trigger["component"] = name
+		inline = append(inline, InlineComponent{Name: name, TriggerType: triggerType, Index: i, Component: component})
+	}
+
+	return inline, nil
+}
+
 // This was a ChatGPT generated function, and helped fix errors with the route struct.
 // Not totally sure why this works while a regular unmarshal is insufficient
 func routeDecodeHook(from reflect.Type, to reflect.Type, data any) (any, error) {
@@ -177,6 +323,12 @@ func (r *Route) UnmarshalTOML(data any) error {
 
 type Executor struct {
 	Type string `toml:"type"`
+
+	// Settings for the "wagi" executor
+	// The function the module is started with, which defaults to "_start"
+	Entrypoint string `toml:"entrypoint"`
+	// The command line arguments, where "${SCRIPT_NAME}" and "${ARGS}" are replaced per request
+	Argv string `toml:"argv"`
 }
 
 type Component struct {
@@ -187,6 +339,99 @@ type Component struct {
 	KeyValueStores       []string          `toml:"key_value_stores"`
 	AIModels             []string          `toml:"ai_models"`
 	SQLiteDatabases      []string          `toml:"sqlite_databases"`
+	Tool                 ComponentTool     `toml:"tool"`
+
+	// The environment variables visible to the component through WASI
+	Environment map[string]string `toml:"environment"`
+
+	// The components composed into this one, keyed by the imported interface or package (e.g. "aws:client/s3")
+	Dependencies map[string]Dependency `toml:"dependencies"`
+	// Whether the dependencies can read the component variables and use its outbound hosts
+	DependenciesInheritConfiguration bool `toml:"dependencies_inherit_configuration"`
+
+	// The type of the trigger this component is defined inline in, or blank for a "[component.<name>]" table
+	InlineTrigger string `toml:"-"`
+}
+
+// Dependency is where a component dependency comes from: a registry package, a local Wasm file or an HTTP URL
+type Dependency struct {
+	// Set for a registry package. A plain version string is shorthand for a package named after the import.
+	Version  string
+	Registry string
+	Package  string
+
+	// Set for a local Wasm file
+	Path string
+
+	// Set for a Wasm file downloaded over HTTP, which must be pinned with a digest
+	URL    string
+	Digest string
+
+	// The export of the dependency that satisfies the import, if it is not named after the import
+	Export string
+
+	// The keys of the dependency table that blueprint doesn't know, sorted. A newer Spin may accept them,
+	// so they are reported by "lint" and "deps" rather than failing the whole manifest.
+	UnknownKeys []string
+}
+
+// UnmarshalTOML (for *Dependency) is a function that the `toml` package will call when
+// it encounters a Dependency data structure. It must be named UnmarshalTOML, regardless of the type
+func (d *Dependency) UnmarshalTOML(data any) error {
+	switch v := data.(type) {
+	case string:
+		d.Version = v
+	case map[string]any:
+		fields := map[string]*string{
+			"version":  &d.Version,
+			"registry": &d.Registry,
+			"package":  &d.Package,
+			"path":     &d.Path,
+			"url":      &d.URL,
+			"digest":   &d.Digest,
+			"export":   &d.Export,
+		}
+		for key, value := range v {
+			field, ok := fields[key]
+			if !ok {
+				d.UnknownKeys = append(d.UnknownKeys, key)
+				continue
+			}
+			str, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("expected dependency field %q to be a string", key)
+			}
+			*field = str
+		}
+		sort.Strings(d.UnknownKeys)
+	default:
+		return fmt.Errorf("invalid type for Dependency: %T", v)
+	}
+
+	var kinds int
+	for _, set := range []bool{d.Version != "", d.Path != "", d.URL != ""} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return fmt.Errorf("a dependency must have exactly one of \"version\", \"path\" or \"url\"")
+	}
+	if d.URL != "" && d.Digest == "" {
+		return fmt.Errorf("the dependency at %q must have a \"digest\"", d.URL)
+	}
+
+	return nil
+}
+
+// ComponentTool holds the tool-specific "[component.<name>.tool.<tool>]" tables, which Spin itself ignores
+type ComponentTool struct {
+	Blueprint BlueprintComponentTool `toml:"blueprint"`
+}
+
+type BlueprintComponentTool struct {
+	// Free-form labels used to group components, e.g. in policy files
+	Tags []string `toml:"tags"`
 }
 
 type Source struct {
@@ -208,8 +453,9 @@ func (s *Source) UnmarshalTOML(data any) error {
 		if !ok {
 			return fmt.Errorf("expected URL to be a string")
 		}
+		// The digest is optional, so it is only validated if present
 		digest, ok := v["digest"].(string)
-		if !ok {
+		if _, exists := v["digest"]; exists && !ok {
 			return fmt.Errorf("expected Digest to be a string")
 		}
 		s.Struct = &struct {
@@ -3,6 +3,7 @@ package cmd
 import (
 	"testing"
 
+	"github.com/BurntSushi/toml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 )
@@ -24,6 +25,17 @@ func TestParseSpinTOML(t *testing.T) {
 				"missing_required_var": {Required: true},
 				"test_default_var":     {Default: "another_val"},
 			},
+			Tool: Tool{
+				Blueprint: BlueprintTool{
+					Variables: map[string]VariableConstraint{
+						"test_var": {
+							Description: "A variable used for testing",
+							Enum:        []string{"test", "other"},
+							Example:     "test",
+						},
+					},
+				},
+			},
 			Application: Application{
 				Name:        "Test Spin TOML",
 				Version:     "0.1.0",
@@ -131,3 +143,65 @@ func TestParseSpinTOML(t *testing.T) {
 		})
 	}
 }
+
+func TestParseInlineComponents(t *testing.T) {
+	got, err := parseSpinToml("../test_data/spin_inline.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantHTTP := []HTTPTrigger{
+		{Route: Route{String: "/api/..."}, Component: "api"},
+		// "inline-http-2" is taken by a component table, so a suffix is added
+		{Route: Route{String: "/static/..."}, Component: "inline-http-2-2"},
+	}
+	if diff := cmp.Diff(wantHTTP, got.Trigger.HTTP, cmpopts.IgnoreUnexported(Route{})); diff != "" {
+		t.Errorf("parseSpinToml() HTTP triggers mismatch (-want +got):\n%s", diff)
+	}
+
+	wantRedis := []RedisTrigger{{Channel: "jobs", Component: "inline-redis-1"}}
+	if diff := cmp.Diff(wantRedis, got.Trigger.Redis); diff != "" {
+		t.Errorf("parseSpinToml() Redis triggers mismatch (-want +got):\n%s", diff)
+	}
+
+	wantOther := []OtherTrigger{{Component: "inline-cron-1", TriggerType: "cron", Config: map[string]any{"cron_expression": "0 0 * * * *"}}}
+	if diff := cmp.Diff(wantOther, got.Trigger.Other); diff != "" {
+		t.Errorf("parseSpinToml() other triggers mismatch (-want +got):\n%s", diff)
+	}
+
+	wantComponents := map[string]Component{
+		"api":           {Source: Source{String: "api/main.wasm"}},
+		"inline-http-2": {Source: Source{String: "taken/main.wasm"}},
+		"inline-http-2-2": {
+			Source: Source{Struct: &struct {
+				URL    string `toml:"url"`
+				Digest string `toml:"digest"`
+			}{
+				URL:    "https://github.com/fermyon/spin-fileserver/releases/download/v0.3.0/spin_static_fs.wasm",
+				Digest: "sha256:ef88708817e107bf49985c7cefe4dd1f199bf26f6727819183d5c996baa3d148",
+			}},
+			InlineTrigger: "http",
+		},
+		"inline-redis-1": {
+			Source:               Source{String: "worker/main.wasm"},
+			AllowedOutboundHosts: []string{"redis://localhost:6379"},
+			Variables:            map[string]string{"queue": "{{ queue_name }}"},
+			InlineTrigger:        "redis",
+		},
+		"inline-cron-1": {Source: Source{String: "cron/main.wasm"}, InlineTrigger: "cron"},
+	}
+	if diff := cmp.Diff(wantComponents, got.Component, cmpopts.IgnoreUnexported(Source{})); diff != "" {
+		t.Errorf("parseSpinToml() components mismatch (-want +got):\n%s", diff)
+	}
+
+	if len(got.Trigger.Inline) != 0 {
+		t.Errorf("expected the inline components to be merged, got %d left", len(got.Trigger.Inline))
+	}
+}
+
+func TestParseMalformedHTTPTrigger(t *testing.T) {
+	var tomlData SpinTOML
+	if _, err := toml.Decode("[[trigger.http]]\nroute = 1\ncomponent = \"api\"\n", &tomlData); err == nil {
+		t.Errorf("expected an error for a malformed HTTP trigger")
+	}
+}
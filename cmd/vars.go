@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var varsCmd = &cobra.Command{
+	Use:   "vars [--matrix env-file...]",
+	Short: "Display the resolved values of the variables in a Spin application",
+	Long: `The "vars" command reads a spin.toml file and prints the final value of every top-level variable.
+With "--explain", it also shows where each value came from (the process environment, a ".env" file and line,
+a runtime config provider or the manifest default) and which top-level variables each component variable references.
+With "--matrix", it compares the values of every variable across several ".env" files, one column per file.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		explain, err := cmd.Flags().GetBool("explain")
+		if err != nil {
+			return err
+		}
+
+		matrix, err := cmd.Flags().GetBool("matrix")
+		if err != nil {
+			return err
+		}
+
+		if matrix {
+			if len(args) == 0 {
+				return fmt.Errorf("the \"--matrix\" flag requires at least one \".env\" file")
+			}
+
+			tomlData, _, err := loadApp(cmd)
+			if err != nil {
+				return err
+			}
+
+			terminalOutput, err := showVariableMatrix(tomlData, args)
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(terminalOutput)
+			return nil
+		}
+
+		if len(args) > 0 {
+			return fmt.Errorf("unexpected arguments %q, \".env\" files can only be passed with \"--matrix\"", args)
+		}
+
+		tomlData, envVars, err := loadApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(showVariables(tomlData, envVars, explain))
+
+		return nil
+	},
+}
+
+func init() {
+	varsCmd.Flags().Bool("explain", false, "Show where the value of every variable came from")
+	varsCmd.Flags().Bool("matrix", false, "Compare the variables across the \".env\" files passed as arguments")
+}
+
+// The kinds of places a variable value can come from
+const (
+	SourceProcessEnv = "env"
+	SourceDotenv     = "dotenv"
+	SourceVault      = "vault"
+	SourceDefault    = "default"
+	SourceUnresolved = "unresolved"
+)
+
+// VariableSource describes where the value of a variable came from
+type VariableSource struct {
+	Kind string
+	// The ".env" file and line the value was read from (only set for SourceDotenv),
+	// or the path of the secret for SourceVault
+	File string
+	Line int
+}
+
+func (s VariableSource) String() string {
+	switch s.Kind {
+	case SourceProcessEnv:
+		return "process env"
+	case SourceDotenv:
+		return fmt.Sprintf("%s:%d", s.File, s.Line)
+	case SourceVault:
+		return "vault " + s.File
+	case SourceDefault:
+		return "manifest default"
+	default:
+		return "unresolved"
+	}
+}
+
+// VariableValue is the value of a variable along with where it came from
+type VariableValue struct {
+	Value  string
+	Source VariableSource
+}
+
+// resolveVariables determines the final value of every top-level variable declared in the "spin.toml" file.
+// Values passed via env vars take precedence over the manifest defaults.
+func resolveVariables(tomlData *SpinTOML, envVars map[string]VariableValue) map[string]VariableValue {
+	resolved := make(map[string]VariableValue, len(tomlData.Variables))
+	for varKey, varData := range tomlData.Variables {
+		if envVar, ok := envVars[varKey]; ok {
+			resolved[varKey] = envVar
+		} else if varData.Default != "" {
+			resolved[varKey] = VariableValue{Value: varData.Default, Source: VariableSource{Kind: SourceDefault}}
+		} else {
+			resolved[varKey] = VariableValue{Source: VariableSource{Kind: SourceUnresolved}}
+		}
+	}
+
+	return resolved
+}
+
+// explainTemplateRefs describes the top-level variables referenced by a component variable template and where their values came from
+func explainTemplateRefs(varString string, resolvedVars map[string]VariableValue) string {
+	refs := templateVarRefs(varString)
+	if len(refs) == 0 {
+		return "literal"
+	}
+
+	var explanations []string
+	for _, ref := range refs {
+		source := VariableSource{Kind: SourceUnresolved}
+		if resolved, ok := resolvedVars[ref]; ok {
+			source = resolved.Source
+		}
+		explanations = append(explanations, ref+" <- "+source.String())
+	}
+
+	return strings.Join(explanations, "\n")
+}
+
+// showVariables will display a table with the final value of every top-level variable.
+// If explain is set, the sources of the values and the component variables are displayed too.
+func showVariables(tomlData *SpinTOML, envVars map[string]VariableValue, explain bool) string {
+	resolvedVars := resolveVariables(tomlData, envVars)
+
+	var varKeys []string
+	for varKey := range tomlData.Variables {
+		varKeys = append(varKeys, varKey)
+	}
+	sort.Strings(varKeys)
+
+	variableTable := table.NewWriter()
+	variableTable.SetTitle("Variables")
+	if explain {
+		variableTable.AppendHeader(table.Row{"var_key", "var_value", "source"})
+	} else {
+		variableTable.AppendHeader(table.Row{"var_key", "var_value"})
+	}
+
+	for _, varKey := range varKeys {
+		resolved := resolvedVars[varKey]
+		value := maskSecret(resolved.Value, tomlData.Variables[varKey].Secret)
+		if resolved.Source.Kind == SourceUnresolved {
+			value = "ERR: UNRESOLVED"
+		}
+
+		if explain {
+			variableTable.AppendRow(table.Row{varKey, value, resolved.Source})
+		} else {
+			variableTable.AppendRow(table.Row{varKey, value})
+		}
+	}
+
+	outputString := "\n" + variableTable.Render()
+	if !explain {
+		return outputString + "\n"
+	}
+
+	componentVarTable := table.NewWriter()
+	componentVarTable.SetTitle("Component Variables")
+	componentVarTable.AppendHeader(table.Row{"component", "var_key", "var_value", "template", "references"})
+
+	var componentNames []string
+	for name := range tomlData.Component {
+		componentNames = append(componentNames, name)
+	}
+	sort.Strings(componentNames)
+
+	// Secrets are masked before the templates are parsed, so they are also hidden inside substituted values
+	values := maskSecrets(tomlData, resolvedVars)
+	for _, name := range componentNames {
+		componentVars := tomlData.Component[name].Variables
+
+		var compVarKeys []string
+		for compVarKey := range componentVars {
+			compVarKeys = append(compVarKeys, compVarKey)
+		}
+		sort.Strings(compVarKeys)
+
+		for _, compVarKey := range compVarKeys {
+			compVarValue := componentVars[compVarKey]
+			parsedVal, err := parseComponentVar(compVarValue, values)
+			if err != nil {
+				parsedVal = "ERR: " + err.Error()
+			}
+
+			componentVarTable.AppendRow(table.Row{name, compVarKey, parsedVal, compVarValue, explainTemplateRefs(compVarValue, resolvedVars)})
+		}
+	}
+
+	return outputString + "\n\n" + componentVarTable.Render() + "\n"
+}
+
+// envFileNames returns the names the ".env" files are shown with, which is their file name
+// unless several files have the same one (e.g. "dev/.env" and "prod/.env"), in which case it is their path
+func envFileNames(envFiles []string) []string {
+	counts := make(map[string]int)
+	for _, envFile := range envFiles {
+		counts[filepath.Base(envFile)]++
+	}
+
+	names := make([]string, len(envFiles))
+	for i, envFile := range envFiles {
+		names[i] = filepath.Base(envFile)
+		if counts[names[i]] > 1 {
+			names[i] = envFile
+		}
+	}
+
+	return names
+}
+
+// showVariableMatrix will display a table comparing the value of every top-level variable across several ".env" files.
+// Missing required values, values that fell back to the default and values that differ between the files are noted.
+func showVariableMatrix(tomlData *SpinTOML, envFiles []string) (string, error) {
+	envNames := envFileNames(envFiles)
+	environments := make([]map[string]VariableValue, len(envFiles))
+	header := table.Row{"var_key"}
+	for i, envFile := range envFiles {
+		envVars, err := parseEnvVars(envFile)
+		if err != nil {
+			return "", err
+		}
+
+		environments[i] = resolveVariables(tomlData, envVars)
+		header = append(header, envNames[i])
+	}
+	header = append(header, "notes")
+
+	var varKeys []string
+	for varKey := range tomlData.Variables {
+		varKeys = append(varKeys, varKey)
+	}
+	sort.Strings(varKeys)
+
+	matrixTable := table.NewWriter()
+	matrixTable.SetTitle("Variable Matrix")
+	matrixTable.AppendHeader(header)
+
+	for _, varKey := range varKeys {
+		varData := tomlData.Variables[varKey]
+		row := table.Row{varKey}
+		var notes, missing, defaulted []string
+		distinctValues := make(map[string]bool)
+
+		for i, resolvedVars := range environments {
+			resolved := resolvedVars[varKey]
+			envName := envNames[i]
+
+			switch resolved.Source.Kind {
+			case SourceUnresolved:
+				if varData.Required {
+					row = append(row, "ERR: MISSING REQUIRED VALUE")
+					missing = append(missing, envName)
+				} else {
+					row = append(row, "ERR: UNRESOLVED")
+				}
+				continue
+			case SourceDefault:
+				row = append(row, maskSecret(resolved.Value, varData.Secret)+" (default)")
+				defaulted = append(defaulted, envName)
+			default:
+				row = append(row, maskSecret(resolved.Value, varData.Secret))
+			}
+			distinctValues[resolved.Value] = true
+		}
+
+		if len(missing) > 0 {
+			notes = append(notes, "missing required value in "+strings.Join(missing, ", "))
+		}
+		if len(defaulted) > 0 && len(defaulted) < len(environments) {
+			notes = append(notes, "default used in "+strings.Join(defaulted, ", "))
+		}
+		if len(distinctValues) > 1 {
+			notes = append(notes, "values differ")
+		}
+
+		matrixTable.AppendRow(append(row, strings.Join(notes, "\n")))
+	}
+
+	return "\n" + matrixTable.Render() + "\n", nil
+}
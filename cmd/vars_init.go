@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var varsInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Generate a \".env.example\" file from the variables in a spin.toml file",
+	Long: `The "vars init" command reads a spin.toml file and writes a ".env.example" file containing a
+"SPIN_VARIABLE_<NAME>" line for every top-level variable. Each line is commented with whether the variable
+is required or secret, its default value and the components that consume it.
+With "--format json", the same contract is written as JSON for deployment tooling.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		force, err := cmd.Flags().GetBool("force")
+		if err != nil {
+			return err
+		}
+
+		tomlData, _, err := loadApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		contracts := buildVariableContracts(tomlData)
+
+		var content string
+		switch format {
+		case "env":
+			content = contractsToDotenv(contracts)
+		case "json":
+			out, err := json.MarshalIndent(contracts, "", "  ")
+			if err != nil {
+				return err
+			}
+			content = string(out) + "\n"
+		default:
+			return fmt.Errorf("unknown format %q, expected \"env\" or \"json\"", format)
+		}
+
+		if output == "-" {
+			fmt.Print(content)
+			return nil
+		}
+
+		if output == "" {
+			output = ".env.example"
+			if format == "json" {
+				output = "variables.json"
+			}
+		}
+
+		if _, err := os.Stat(output); err == nil && !force {
+			return fmt.Errorf("the file %q already exists, use \"--force\" to overwrite it", output)
+		}
+
+		if err := os.WriteFile(output, []byte(content), 0o644); err != nil {
+			return err
+		}
+
+		fmt.Printf("Wrote %d variable(s) to %q\n", len(contracts), output)
+		return nil
+	},
+}
+
+func init() {
+	varsInitCmd.Flags().StringP("output", "o", "", "The file to write, or \"-\" for stdout (defaults to \".env.example\", or \"variables.json\" for JSON)")
+	varsInitCmd.Flags().String("format", "env", "The output format, either \"env\" or \"json\"")
+	varsInitCmd.Flags().Bool("force", false, "Overwrite the output file if it already exists")
+	varsCmd.AddCommand(varsInitCmd)
+}
+
+// VariableContract describes a top-level variable that must (or may) be provided to run the application
+type VariableContract struct {
+	Name     string `json:"name"`
+	EnvKey   string `json:"env_key"`
+	Required bool   `json:"required"`
+	Secret   bool   `json:"secret"`
+	// This needs to be a pointer, so a blank default is distinguishable from no default
+	Default     *string  `json:"default,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Example     string   `json:"example,omitempty"`
+	Components  []string `json:"components"`
+}
+
+// variableConsumers maps every variable name to the components with a templated field that references it
+func variableConsumers(tomlData *SpinTOML) map[string][]string {
+	consumers := make(map[string][]string)
+	for _, ref := range findVariableRefs(tomlData) {
+		// The references are sorted by component, so repeated references from one component are adjacent
+		components := consumers[ref.Variable]
+		if ref.Component != "" && (len(components) == 0 || components[len(components)-1] != ref.Component) {
+			consumers[ref.Variable] = append(components, ref.Component)
+		}
+	}
+
+	return consumers
+}
+
+// buildVariableContracts describes every top-level variable in the "spin.toml" file, sorted by name
+func buildVariableContracts(tomlData *SpinTOML) []VariableContract {
+	consumers := variableConsumers(tomlData)
+
+	contracts := []VariableContract{}
+	for varKey, varData := range tomlData.Variables {
+		contract := VariableContract{
+			Name:       varKey,
+			EnvKey:     "SPIN_VARIABLE_" + strings.ToUpper(varKey),
+			Required:   varData.Required,
+			Secret:     varData.Secret,
+			Components: consumers[varKey],
+		}
+		if constraint, ok := tomlData.Tool.Blueprint.Variables[varKey]; ok {
+			contract.Description = constraint.Description
+			contract.Example = constraint.Example
+		}
+		if varData.Default != "" {
+			// Secret defaults are masked, so the contract can be shared safely
+			defaultValue := maskSecret(varData.Default, varData.Secret)
+			contract.Default = &defaultValue
+		}
+		if contract.Components == nil {
+			contract.Components = []string{}
+		}
+
+		contracts = append(contracts, contract)
+	}
+
+	sort.Slice(contracts, func(i, j int) bool {
+		return contracts[i].Name < contracts[j].Name
+	})
+
+	return contracts
+}
+
+// contractsToDotenv renders the variable contracts as a ".env.example" file.
+// Variables with a default are commented out, so the default applies unless the line is uncommented.
+func contractsToDotenv(contracts []VariableContract) string {
+	var sb strings.Builder
+	sb.WriteString("# Generated by \"spin blueprint vars init\". Copy this file to \".env\" and fill in the values.\n")
+
+	for _, contract := range contracts {
+		var status []string
+		if contract.Required {
+			status = append(status, "required")
+		} else {
+			status = append(status, "optional")
+		}
+		if contract.Secret {
+			status = append(status, "secret")
+		}
+		if contract.Default != nil {
+			status = append(status, fmt.Sprintf("default: %q", *contract.Default))
+		}
+
+		usedBy := "no components"
+		if len(contract.Components) > 0 {
+			usedBy = strings.Join(contract.Components, ", ")
+		}
+
+		fmt.Fprintf(&sb, "\n# %s (%s)\n", contract.Name, strings.Join(status, ", "))
+		if contract.Description != "" {
+			fmt.Fprintf(&sb, "# %s\n", contract.Description)
+		}
+		if contract.Example != "" {
+			fmt.Fprintf(&sb, "# Example: %s\n", contract.Example)
+		}
+		fmt.Fprintf(&sb, "# Used by: %s\n", usedBy)
+		if contract.Default != nil {
+			fmt.Fprintf(&sb, "# %s=%s\n", contract.EnvKey, *contract.Default)
+		} else {
+			fmt.Fprintf(&sb, "%s=\n", contract.EnvKey)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBuildVariableContracts(t *testing.T) {
+	tomlData, err := parseSpinToml("../test_data/spin.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defaultOf := func(value string) *string { return &value }
+	want := []VariableContract{
+		{Name: "missing_default_var", EnvKey: "SPIN_VARIABLE_MISSING_DEFAULT_VAR", Components: []string{}},
+		{Name: "missing_required_var", EnvKey: "SPIN_VARIABLE_MISSING_REQUIRED_VAR", Required: true, Components: []string{}},
+		{Name: "override_default_var", EnvKey: "SPIN_VARIABLE_OVERRIDE_DEFAULT_VAR", Default: defaultOf("some_val"), Components: []string{}},
+		{Name: "secret_var", EnvKey: "SPIN_VARIABLE_SECRET_VAR", Secret: true, Components: []string{"number-one"}},
+		{Name: "test_default_var", EnvKey: "SPIN_VARIABLE_TEST_DEFAULT_VAR", Default: defaultOf("another_val"), Components: []string{"number-one"}},
+		{Name: "test_var", EnvKey: "SPIN_VARIABLE_TEST_VAR", Default: defaultOf("test"), Description: "A variable used for testing", Example: "test", Components: []string{"number-one"}},
+	}
+
+	contracts := buildVariableContracts(tomlData)
+	if diff := cmp.Diff(want, contracts); diff != "" {
+		t.Errorf("buildVariableContracts() mismatch (-want +got):\n%s", diff)
+	}
+
+	// The generated file must be readable by the ".env" parser
+	entries, err := parseDotenv(strings.NewReader(contractsToDotenv(contracts)), func(string) (string, bool) { return "", false })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var keys []string
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+
+	// Variables with a default are commented out
+	wantKeys := []string{"SPIN_VARIABLE_MISSING_DEFAULT_VAR", "SPIN_VARIABLE_MISSING_REQUIRED_VAR", "SPIN_VARIABLE_SECRET_VAR"}
+	if diff := cmp.Diff(wantKeys, keys); diff != "" {
+		t.Errorf("contractsToDotenv() keys mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuildVariableContractsSecretDefault(t *testing.T) {
+	tomlData := &SpinTOML{
+		Variables: map[string]Variable{
+			"api_key": {Default: "hunter2", Secret: true},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		revealSecrets bool
+		want          string
+	}{
+		{name: "masked", want: secretMask},
+		{name: "revealed", revealSecrets: true, want: "hunter2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RevealSecrets = tt.revealSecrets
+			defer func() { RevealSecrets = false }()
+
+			contracts := buildVariableContracts(tomlData)
+			if got := contracts[0].Default; got == nil || *got != tt.want {
+				t.Errorf("got default %v, want %q", got, tt.want)
+			}
+
+			out, err := json.Marshal(contracts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.revealSecrets && strings.Contains(string(out), "hunter2") {
+				t.Errorf("expected the secret default to be masked in the JSON contract, got %s", out)
+			}
+		})
+	}
+}
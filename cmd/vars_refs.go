@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+)
+
+var varsRefsCmd = &cobra.Command{
+	Use:   "refs",
+	Short: "Display where every variable in a spin.toml file is referenced",
+	Long: `The "vars refs" command reads a spin.toml file and lists every component and field that references
+each top-level variable. It also lists the declared variables that nothing references, and the templates
+that reference variables which are not declared.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tomlData, _, err := loadApp(cmd)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(showVariableRefs(tomlData))
+
+		return nil
+	},
+}
+
+func init() {
+	varsCmd.AddCommand(varsRefsCmd)
+}
+
+// VariableRef is a single reference to a variable from a templated field in the "spin.toml" file
+type VariableRef struct {
+	Variable string
+	// The component the field belongs to, or blank for application-level fields
+	Component string
+	Field     string
+}
+
+// findVariableRefs scans every templated field in the "spin.toml" file for variable references.
+// The references are sorted by component, then by field.
+func findVariableRefs(tomlData *SpinTOML) []VariableRef {
+	var refs []VariableRef
+	addRefs := func(component, field, template string) {
+		for _, ref := range templateVarRefs(template) {
+			refs = append(refs, VariableRef{Variable: ref, Component: component, Field: field})
+		}
+	}
+
+	addRefs("", "application.trigger.redis.address", tomlData.Application.Trigger.Redis.Address)
+
+	for name, componentData := range tomlData.Component {
+		for varKey, varValue := range componentData.Variables {
+			addRefs(name, "variables."+varKey, varValue)
+		}
+		for i, host := range componentData.AllowedOutboundHosts {
+			addRefs(name, fmt.Sprintf("allowed_outbound_hosts[%d]", i), host)
+		}
+		for envKey, envValue := range componentData.Environment {
+			addRefs(name, "environment."+envKey, envValue)
+		}
+	}
+
+	for i, httpTrigger := range tomlData.Trigger.HTTP {
+		addRefs(httpTrigger.Component, fmt.Sprintf("trigger.http[%d].executor.entrypoint", i), httpTrigger.Executor.Entrypoint)
+		addRefs(httpTrigger.Component, fmt.Sprintf("trigger.http[%d].executor.argv", i), httpTrigger.Executor.Argv)
+	}
+
+	// The plugin trigger settings can be lists and tables, so every string in them is scanned
+	var addSettingRefs func(component, field string, value any)
+	addSettingRefs = func(component, field string, value any) {
+		switch v := value.(type) {
+		case string:
+			addRefs(component, field, v)
+		case []any:
+			for i, item := range v {
+				addSettingRefs(component, fmt.Sprintf("%s[%d]", field, i), item)
+			}
+		case map[string]any:
+			for key, item := range v {
+				addSettingRefs(component, field+"."+key, item)
+			}
+		}
+	}
+
+	// The application-level settings of a trigger type apply to every trigger of that type,
+	// so they are referenced by the components of those triggers
+	triggerComponents := make(map[string][]string)
+	for _, otherTrigger := range tomlData.Trigger.Other {
+		if !slices.Contains(triggerComponents[otherTrigger.TriggerType], otherTrigger.Component) {
+			triggerComponents[otherTrigger.TriggerType] = append(triggerComponents[otherTrigger.TriggerType], otherTrigger.Component)
+		}
+	}
+	for triggerType, settings := range tomlData.Application.Trigger.Other {
+		components := triggerComponents[triggerType]
+		if len(components) == 0 {
+			components = []string{""}
+		}
+		for _, component := range components {
+			for key, value := range settings {
+				addSettingRefs(component, "application.trigger."+triggerType+"."+key, value)
+			}
+		}
+	}
+
+	// The triggers are numbered per type, like the "[[trigger.<type>]]" tables they come from
+	otherIndexes := make(map[string]int)
+	for _, otherTrigger := range tomlData.Trigger.Other {
+		prefix := fmt.Sprintf("trigger.%s[%d]", otherTrigger.TriggerType, otherIndexes[otherTrigger.TriggerType])
+		otherIndexes[otherTrigger.TriggerType]++
+		for key, value := range otherTrigger.Config {
+			addSettingRefs(otherTrigger.Component, prefix+"."+key, value)
+		}
+	}
+
+	for i, redisTrigger := range tomlData.Trigger.Redis {
+		addRefs(redisTrigger.Component, fmt.Sprintf("trigger.redis[%d].address", i), redisTrigger.Address)
+		addRefs(redisTrigger.Component, fmt.Sprintf("trigger.redis[%d].channel", i), redisTrigger.Channel)
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool {
+		if refs[i].Component != refs[j].Component {
+			return refs[i].Component < refs[j].Component
+		}
+		return refs[i].Field < refs[j].Field
+	})
+
+	return refs
+}
+
+// showVariableRefs will display tables with the references to every variable, the unused variables and the undeclared references
+func showVariableRefs(tomlData *SpinTOML) string {
+	refs := findVariableRefs(tomlData)
+
+	referenced := make(map[string]bool)
+	var undeclared []VariableRef
+	refsByVariable := make(map[string][]VariableRef)
+	for _, ref := range refs {
+		referenced[ref.Variable] = true
+		if _, ok := tomlData.Variables[ref.Variable]; ok {
+			refsByVariable[ref.Variable] = append(refsByVariable[ref.Variable], ref)
+		} else {
+			undeclared = append(undeclared, ref)
+		}
+	}
+
+	var varKeys, unused []string
+	for varKey := range tomlData.Variables {
+		varKeys = append(varKeys, varKey)
+		if !referenced[varKey] {
+			unused = append(unused, varKey)
+		}
+	}
+	sort.Strings(varKeys)
+	sort.Strings(unused)
+
+	refTable := table.NewWriter()
+	refTable.SetTitle("Variable References")
+	refTable.AppendHeader(table.Row{"var_key", "component", "field"})
+	for _, varKey := range varKeys {
+		for _, ref := range refsByVariable[varKey] {
+			refTable.AppendRow(table.Row{varKey, displayComponent(ref.Component), ref.Field})
+		}
+	}
+
+	outputString := "\n" + refTable.Render()
+
+	if len(unused) > 0 {
+		unusedTable := table.NewWriter()
+		unusedTable.SetTitle("Unused Variables")
+		unusedTable.AppendHeader(table.Row{"var_key", "is_required", "default"})
+		for _, varKey := range unused {
+			varData := tomlData.Variables[varKey]
+			unusedTable.AppendRow(table.Row{varKey, varData.Required, maskSecret(varData.Default, varData.Secret)})
+		}
+		outputString += "\n\n" + unusedTable.Render()
+	}
+
+	if len(undeclared) > 0 {
+		undeclaredTable := table.NewWriter()
+		undeclaredTable.SetTitle("Undeclared References")
+		undeclaredTable.AppendHeader(table.Row{"var_key", "component", "field"})
+		for _, ref := range undeclared {
+			undeclaredTable.AppendRow(table.Row{ref.Variable, displayComponent(ref.Component), ref.Field})
+		}
+		outputString += "\n\n" + undeclaredTable.Render()
+	}
+
+	return outputString + "\n"
+}
+
+// displayComponent labels application-level fields, which don't belong to a component
+func displayComponent(component string) string {
+	if component == "" {
+		return "(application)"
+	}
+
+	return component
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindVariableRefs(t *testing.T) {
+	tomlData := &SpinTOML{
+		Application: Application{
+			Trigger: ApplicationTrigger{
+				Redis: ApplicationTriggerRedis{Address: "redis://{{ redis_host }}:6379"},
+			},
+		},
+		Trigger: Trigger{
+			Redis: []RedisTrigger{
+				{Channel: "{{ channel }}", Component: "worker"},
+			},
+			Other: []OtherTrigger{
+				{TriggerType: "kafka", Component: "worker", Config: map[string]any{"topics": []any{"static", "{{ channel }}"}}},
+			},
+		},
+		Component: map[string]Component{
+			"api": {
+				Variables:            map[string]string{"url": "https://{{ api_host }}/{{ api_version }}", "static": "value"},
+				AllowedOutboundHosts: []string{"https://{{ api_host }}"},
+			},
+			"worker": {},
+		},
+	}
+
+	want := []VariableRef{
+		{Variable: "redis_host", Field: "application.trigger.redis.address"},
+		{Variable: "api_host", Component: "api", Field: "allowed_outbound_hosts[0]"},
+		{Variable: "api_host", Component: "api", Field: "variables.url"},
+		{Variable: "api_version", Component: "api", Field: "variables.url"},
+		{Variable: "channel", Component: "worker", Field: "trigger.kafka[0].topics[1]"},
+		{Variable: "channel", Component: "worker", Field: "trigger.redis[0].channel"},
+	}
+
+	if diff := cmp.Diff(want, findVariableRefs(tomlData)); diff != "" {
+		t.Errorf("findVariableRefs() mismatch (-want +got):\n%s", diff)
+	}
+
+	wantConsumers := map[string][]string{
+		"api_host":    {"api"},
+		"api_version": {"api"},
+		"channel":     {"worker"},
+	}
+	if diff := cmp.Diff(wantConsumers, variableConsumers(tomlData)); diff != "" {
+		t.Errorf("variableConsumers() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFindVariableRefsTriggers(t *testing.T) {
+	tomlData, err := parseSpinToml("../test_data/spin_triggers.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []VariableRef{
+		{Variable: "mqtt_user", Component: "batch", Field: "application.trigger.mqtt.username"},
+		{Variable: "log_level", Component: "batch", Field: "environment.LOG_LEVEL"},
+		{Variable: "cgi_script", Component: "batch", Field: "trigger.http[0].executor.argv"},
+		{Variable: "queue_url", Component: "batch", Field: "trigger.sqs[0].queue_url"},
+	}
+
+	if diff := cmp.Diff(want, findVariableRefs(tomlData)); diff != "" {
+		t.Errorf("findVariableRefs() mismatch (-want +got):\n%s", diff)
+	}
+
+	// "vars init" lists the components using each variable, so the environment, the executor and the trigger settings must count
+	wantConsumers := map[string][]string{
+		"cgi_script": {"batch"},
+		"log_level":  {"batch"},
+		"mqtt_user":  {"batch"},
+		"queue_url":  {"batch"},
+	}
+	if diff := cmp.Diff(wantConsumers, variableConsumers(tomlData)); diff != "" {
+		t.Errorf("variableConsumers() mismatch (-want +got):\n%s", diff)
+	}
+}
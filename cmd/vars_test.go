@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResolveVariables(t *testing.T) {
+	tomlData, err := parseSpinToml("../test_data/spin.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	envVars, err := parseEnvVars("../test_data/test.env")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dotenv := func(line int) VariableSource {
+		return VariableSource{Kind: SourceDotenv, File: "../test_data/test.env", Line: line}
+	}
+
+	want := map[string]VariableValue{
+		"test_var":             {Value: "test", Source: dotenv(3)},
+		"secret_var":           {Value: "secret", Source: dotenv(4)},
+		"override_default_var": {Value: "overridden_val", Source: dotenv(5)},
+		"missing_default_var":  {Source: VariableSource{Kind: SourceUnresolved}},
+		"missing_required_var": {Source: VariableSource{Kind: SourceUnresolved}},
+		"test_default_var":     {Value: "another_val", Source: VariableSource{Kind: SourceDefault}},
+	}
+
+	got := resolveVariables(tomlData, envVars)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("resolveVariables() mismatch (-want +got):\n%s", diff)
+	}
+
+	tests := []struct {
+		name      string
+		varString string
+		want      string
+	}{
+		{name: "literal", varString: "Hello, world!", want: "literal"},
+		{name: "dotenv", varString: "{{ test_var }}", want: "test_var <- ../test_data/test.env:3"},
+		{name: "default_and_undeclared", varString: "{{ test_default_var }}{{ undeclared }}", want: "test_default_var <- manifest default\nundeclared <- unresolved"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := explainTemplateRefs(tt.varString, got); got != tt.want {
+				t.Errorf("explainTemplateRefs(%q) = %q, want %q", tt.varString, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShowVariableMatrix(t *testing.T) {
+	tomlData, err := parseSpinToml("../test_data/spin.toml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := showVariableMatrix(tomlData, []string{"../test_data/test.env", "../test_data/prod.env"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"| missing_required_var | ERR: MISSING REQUIRED VALUE | present ",
+		"missing required value in test.env",
+		"| override_default_var | overridden_val              | some_val (default)    | default used in prod.env",
+		"| secret_var           | " + secretMask,
+		"| test_var             | test                        | test                  |                                    |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the matrix to contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestParseEnvFilesPrecedence(t *testing.T) {
+	got, err := parseEnvFiles([]string{"../test_data/test.env", "../test_data/prod.env"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]VariableValue{
+		"test_var":             {Value: "test", Source: VariableSource{Kind: SourceDotenv, File: "../test_data/prod.env", Line: 2}},
+		"secret_var":           {Value: "another_secret", Source: VariableSource{Kind: SourceDotenv, File: "../test_data/prod.env", Line: 3}},
+		"missing_required_var": {Value: "present", Source: VariableSource{Kind: SourceDotenv, File: "../test_data/prod.env", Line: 4}},
+		"override_default_var": {Value: "overridden_val", Source: VariableSource{Kind: SourceDotenv, File: "../test_data/test.env", Line: 5}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parseEnvFiles() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestEnvFileNames(t *testing.T) {
+	got := envFileNames([]string{"dev/.env", "prod/.env", "shared/base.env"})
+	want := []string{"dev/.env", "prod/.env", "base.env"}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("envFileNames() mismatch (-want +got):\n%s", diff)
+	}
+}